@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/StackGuardian/sg-cli/dashboard"
+	sggosdk "github.com/StackGuardian/sg-sdk-go"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/StackGuardian/sg-sdk-go/core"
+	"github.com/StackGuardian/sg-sdk-go/option"
+	"golang.org/x/term"
+)
+
+// TokenPrefix is the prefix of every Stackguardian user API token
+const TokenPrefix = "sgu_"
+
+// SourceCredentials is the source reported for tokens read from the credentials file
+const SourceCredentials = "credentials file"
+
+// LoadToken sets the API token of the settings from the credentials file.
+// A token from SG_API_TOKEN always wins; a token stored with `auth login` wins over api-token in the config file.
+// Encrypted tokens are decrypted with SG_CREDENTIALS_PASSPHRASE or a passphrase prompt.
+func LoadToken(settings *config.Settings) error {
+	if strings.HasPrefix(settings.Sources["api-token"], "env") {
+		return nil
+	}
+	credential, err := LoadCredential(settings.ProfileName)
+	if err != nil || credential == nil {
+		return err
+	}
+	passphrase := ""
+	if credential.Encrypted() {
+		passphrase, err = Passphrase("Passphrase for the API token of profile " + settings.ProfileName + ": ")
+		if err != nil {
+			return err
+		}
+	}
+	token, err := credential.Decrypt(passphrase)
+	if err != nil {
+		return err
+	}
+	settings.APIToken = token
+	settings.Sources["api-token"] = SourceCredentials
+	return nil
+}
+
+// CheckToken returns an error explaining how to get a token when the token is missing or malformed
+func CheckToken(settings *config.Settings) error {
+	if settings.APIToken == "" {
+		return clierrors.New(clierrors.Auth, "no API token found for profile %q. Run \"sg-cli auth login\" or set SG_API_TOKEN.\n%s",
+			settings.ProfileName, tokenHint(settings))
+	}
+	if !strings.HasPrefix(settings.APIToken, TokenPrefix) {
+		return clierrors.New(clierrors.Auth, "invalid API token from %s, Stackguardian API tokens start with %q.\n%s",
+			settings.Sources["api-token"], TokenPrefix, tokenHint(settings))
+	}
+	return nil
+}
+
+func tokenHint(settings *config.Settings) string {
+	if settings.Org == "" {
+		return "Navigate to the settings of your organization on " + settings.DashboardURL + " to get your API token."
+	}
+	return "Navigate to Stackguardian platform to get your API token: " + dashboard.New(settings.DashboardURL).APIKeySettings(settings.Org)
+}
+
+// Validate checks the token with a lightweight API call: reading the organization
+func Validate(ctx context.Context, c *client.Client, token string, org string) (*sggosdk.Organization, error) {
+	response, err := c.Organizations.ReadOrganization(ctx, org, option.WithApiKey("apikey "+token))
+	if err != nil {
+		var apiError *core.APIError
+		if errors.As(err, &apiError) &&
+			(apiError.StatusCode == http.StatusUnauthorized || apiError.StatusCode == http.StatusForbidden) {
+			return nil, clierrors.Wrap(err, "the API token was rejected or has no access to org %q", org)
+		}
+		return nil, err
+	}
+	return response.Msg, nil
+}
+
+// Passphrase returns SG_CREDENTIALS_PASSPHRASE or prompts for the passphrase on the terminal
+func Passphrase(prompt string) (string, error) {
+	if passphrase := os.Getenv("SG_CREDENTIALS_PASSPHRASE"); passphrase != "" {
+		return passphrase, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", clierrors.New(clierrors.Auth, "the stored API token is encrypted, set SG_CREDENTIALS_PASSPHRASE to decrypt it")
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(passphrase), nil
+}
+
+// MaskToken hides all but the prefix and the first characters of the token
+func MaskToken(token string) string {
+	if len(token) <= 8 {
+		return "****"
+	}
+	return token[:8] + "****"
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/config"
+	"golang.org/x/crypto/scrypt"
+	"gopkg.in/yaml.v3"
+)
+
+// Credential is the API token stored by `sg-cli auth login` for a profile.
+// When a passphrase is used, only the encrypted token is stored.
+type Credential struct {
+	Token      string `yaml:"token,omitempty"`
+	Salt       string `yaml:"salt,omitempty"`
+	Nonce      string `yaml:"nonce,omitempty"`
+	Ciphertext string `yaml:"ciphertext,omitempty"`
+}
+
+var errWrongPassphrase = clierrors.New(clierrors.Auth, "failed to decrypt the stored API token, the passphrase is wrong")
+
+// CredentialsPath returns the location of the credentials file
+func CredentialsPath() string {
+	return filepath.Join(config.Dir(), "credentials.yaml")
+}
+
+func loadCredentials() (map[string]*Credential, error) {
+	credentials := map[string]*Credential{}
+	data, err := os.ReadFile(CredentialsPath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return credentials, nil
+		}
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &credentials); err != nil {
+		return nil, err
+	}
+	return credentials, nil
+}
+
+func saveCredentials(credentials map[string]*Credential) error {
+	if err := os.MkdirAll(config.Dir(), 0700); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(credentials)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(CredentialsPath(), data, 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(CredentialsPath(), 0600)
+}
+
+// LoadCredential returns the credential stored for the profile, nil if there is none
+func LoadCredential(profileName string) (*Credential, error) {
+	credentials, err := loadCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return credentials[profileName], nil
+}
+
+// SaveCredential stores the token for the profile, encrypted when a passphrase is given
+func SaveCredential(profileName string, token string, passphrase string) error {
+	credentials, err := loadCredentials()
+	if err != nil {
+		return err
+	}
+	credential := &Credential{Token: token}
+	if passphrase != "" {
+		credential, err = encrypt(token, passphrase)
+		if err != nil {
+			return err
+		}
+	}
+	credentials[profileName] = credential
+	return saveCredentials(credentials)
+}
+
+// DeleteCredential removes the credential of the profile. It reports whether one existed.
+func DeleteCredential(profileName string) (bool, error) {
+	credentials, err := loadCredentials()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := credentials[profileName]; !ok {
+		return false, nil
+	}
+	delete(credentials, profileName)
+	return true, saveCredentials(credentials)
+}
+
+// Encrypted reports whether the token is protected by a passphrase
+func (c *Credential) Encrypted() bool {
+	return c.Ciphertext != ""
+}
+
+// Decrypt returns the plain token
+func (c *Credential) Decrypt(passphrase string) (string, error) {
+	if !c.Encrypted() {
+		return c.Token, nil
+	}
+	salt, err := base64.StdEncoding.DecodeString(c.Salt)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(c.Nonce)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(c.Ciphertext)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	token, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errWrongPassphrase
+	}
+	return string(token), nil
+}
+
+func encrypt(token string, passphrase string) (*Credential, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return &Credential{
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, []byte(token), nil)),
+	}, nil
+}
+
+// newGCM derives an AES-256 key from the passphrase with scrypt
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
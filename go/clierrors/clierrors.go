@@ -0,0 +1,227 @@
+// Package clierrors maps the failures of sg-cli commands to categories with stable exit codes,
+// so scripts can branch on the kind of failure instead of matching error strings.
+package clierrors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/StackGuardian/sg-sdk-go/core"
+)
+
+// Category is the kind of a failure, it is part of the JSON error envelope
+type Category string
+
+const (
+	General        Category = "error"
+	Usage          Category = "usage"
+	Auth           Category = "auth"
+	NotFound       Category = "not_found"
+	Validation     Category = "validation"
+	Conflict       Category = "conflict"
+	RateLimited    Category = "rate_limited"
+	Server         Category = "server"
+	Network        Category = "network"
+	PartialFailure Category = "partial_failure"
+	RunFailed      Category = "run_failed"
+	RunCancelled   Category = "run_cancelled"
+	Busy           Category = "busy"
+	Cancelled      Category = "cancelled"
+	Timeout        Category = "timeout"
+
+	// Changes is not a failure, workflow plan --detailed-exitcode returns it when the plan has changes
+	Changes Category = "changes"
+	// Drift is not a failure, workflow diff returns it when the workflow differs from the payload
+	Drift Category = "drift"
+)
+
+// exitCodes are part of the public interface of sg-cli, existing values must never change
+var exitCodes = map[Category]int{
+	General:        1,
+	Drift:          1,
+	Usage:          2,
+	Changes:        2,
+	Auth:           3,
+	NotFound:       4,
+	Validation:     5,
+	Conflict:       6,
+	RateLimited:    7,
+	Server:         8,
+	Network:        9,
+	PartialFailure: 10,
+	RunFailed:      11,
+	RunCancelled:   12,
+	Busy:           13,
+	Timeout:        124,
+	Cancelled:      130,
+}
+
+// Categories returns all the categories ordered by exit code
+func Categories() []Category {
+	return []Category{General, Drift, Usage, Changes, Auth, NotFound, Validation, Conflict, RateLimited, Server, Network, PartialFailure, RunFailed, RunCancelled, Busy, Timeout, Cancelled}
+}
+
+// conflictMessages are returned by the API with a 400 status code for requests that conflict with existing resources
+var conflictMessages = []string{"not unique", "already exists", "Stack is not empty"}
+
+// Error is a failure of a command with its category
+type Error struct {
+	Category Category
+	Message  string
+	// StatusCode is the HTTP status code of the API response the error is based on, if any
+	StatusCode int
+	// Details are added to the JSON error envelope, e.g. the results of a bulk operation
+	Details interface{}
+	// DetailedExitCode is set when the command ran with --detailed-exitcode, see Detailed
+	DetailedExitCode bool
+	Err              error
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Message == "" && e.Err != nil:
+		return e.Err.Error()
+	case e.Err != nil:
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code of the category of the error
+func (e *Error) ExitCode() int {
+	if e.DetailedExitCode && e.Category != Changes {
+		return exitCodes[General]
+	}
+	if code, ok := exitCodes[e.Category]; ok {
+		return code
+	}
+	return exitCodes[General]
+}
+
+// New returns an error of the given category
+func New(category Category, format string, args ...interface{}) *Error {
+	return &Error{Category: category, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap adds a message to err and classifies it, see From
+func Wrap(err error, format string, args ...interface{}) *Error {
+	if err == nil {
+		return nil
+	}
+	classified := From(err)
+	return &Error{
+		Category:   classified.Category,
+		Message:    fmt.Sprintf(format, args...),
+		StatusCode: classified.StatusCode,
+		Details:    classified.Details,
+		Err:        err,
+	}
+}
+
+// From classifies err. Errors that are already classified are returned as is,
+// API errors are classified by their status code and all other errors are General.
+func From(err error) *Error {
+	var classified *Error
+	if errors.As(err, &classified) {
+		return classified
+	}
+	e := &Error{Category: General, Err: err}
+
+	var apiError *core.APIError
+	var netError net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		e.Category = Timeout
+	case errors.Is(err, context.Canceled):
+		e.Category = Cancelled
+	case errors.As(err, &apiError):
+		e.StatusCode = apiError.StatusCode
+		e.Category = categoryOf(apiError)
+	case errors.As(err, &netError):
+		e.Category = Network
+	case strings.Contains(err.Error(), "the server responded with nothing"):
+		e.Category = NotFound
+	}
+	return e
+}
+
+func categoryOf(apiError *core.APIError) Category {
+	status := apiError.StatusCode
+	switch {
+	case status == 401 || status == 403:
+		return Auth
+	case status == 404:
+		return NotFound
+	case status == 409:
+		return Conflict
+	case status == 429:
+		return RateLimited
+	case status == 408:
+		return Network
+	case status >= 500:
+		return Server
+	case status >= 400:
+		for _, message := range conflictMessages {
+			if strings.Contains(apiError.Error(), message) {
+				return Conflict
+			}
+		}
+		return Validation
+	}
+	return General
+}
+
+// Detailed classifies err for a command run with --detailed-exitcode. Like terraform, every failure
+// then exits with 1 so that 2 only means changes, the category is kept in the JSON error envelope.
+func Detailed(err error) *Error {
+	e := From(err)
+	e.DetailedExitCode = true
+	return e
+}
+
+// ExitCode returns the exit code for err, 0 when err is nil
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	return From(err).ExitCode()
+}
+
+// Envelope is the machine-readable form of an error, printed when JSON output is requested
+type Envelope struct {
+	Error EnvelopeError `json:"error"`
+}
+
+type EnvelopeError struct {
+	Category   Category    `json:"category"`
+	ExitCode   int         `json:"exitCode"`
+	Message    string      `json:"message"`
+	StatusCode int         `json:"statusCode,omitempty"`
+	Details    interface{} `json:"details,omitempty"`
+}
+
+// WriteEnvelope writes err as a JSON error envelope to w
+func WriteEnvelope(w io.Writer, err error) error {
+	e := From(err)
+	data, marshalErr := json.MarshalIndent(Envelope{Error: EnvelopeError{
+		Category:   e.Category,
+		ExitCode:   e.ExitCode(),
+		Message:    e.Error(),
+		StatusCode: e.StatusCode,
+		Details:    e.Details,
+	}}, "", "  ")
+	if marshalErr != nil {
+		return marshalErr
+	}
+	_, writeErr := fmt.Fprintln(w, string(data))
+	return writeErr
+}
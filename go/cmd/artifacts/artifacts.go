@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/StackGuardian/sg-cli/cmd/artifacts/list"
+	"github.com/StackGuardian/sg-cli/cmd/cmdutil"
 	"github.com/StackGuardian/sg-sdk-go/client"
 	"github.com/spf13/cobra"
 )
@@ -11,20 +12,19 @@ import (
 func NewArtifactsCmd(c *client.Client) *cobra.Command {
 	// artifactsCmd represents the Artifacts command
 	var artifactsCmd = &cobra.Command{
-		Use:   "artifacts",
-		Short: "List Artifacts",
-		Long:  `List artifacts on Stackguardian platform.`,
+		Use:               "artifacts",
+		Short:             "List Artifacts",
+		Long:              `List artifacts on Stackguardian platform.`,
+		PersistentPreRunE: cmdutil.ResolveContext,
 		Run: func(cmd *cobra.Command, args []string) {
 			fmt.Println(`Sub-commands:
   list      List Artifacts`)
 		},
 	}
 
-	artifactsCmd.PersistentFlags().String("org", "", "The organization name on Stackguardian platform.")
-	artifactsCmd.MarkPersistentFlagRequired("org")
+	artifactsCmd.PersistentFlags().String("org", "", "The organization name on Stackguardian platform. Defaults to the current context.")
 
-	artifactsCmd.PersistentFlags().String("workflow-group", "", "The workflow group under the organization.")
-	artifactsCmd.MarkPersistentFlagRequired("workflow-group")
+	artifactsCmd.PersistentFlags().String("workflow-group", "", "The workflow group under the organization. Defaults to the current context.")
 
 	artifactsCmd.PersistentFlags().String("workflow-id", "", "The workflow id in the workflow group.")
 	artifactsCmd.MarkPersistentFlagRequired("workflow-id")
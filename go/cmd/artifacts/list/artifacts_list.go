@@ -1,49 +1,40 @@
 package list
 
 import (
-	"context"
-	"os"
 	"strings"
 
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/output"
 	"github.com/StackGuardian/sg-sdk-go/client"
 	"github.com/spf13/cobra"
 )
 
-type RunOptions struct {
-	OutputJson bool
-}
-
 func NewListCmd(c *client.Client) *cobra.Command {
-	opts := &RunOptions{}
 	// listCmd represents the list command
 	var listCmd = &cobra.Command{
 		Use:   "list",
 		Short: "List Artifacts",
 		Long:  `List Artifacts`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
 			response, err := c.Workflows.ListAllWorkflowArtifacts(
-				context.Background(),
+				cmd.Context(),
 				cmd.Parent().Flags().Lookup("org").Value.String(),
 				cmd.Parent().Flags().Lookup("workflow-id").Value.String(),
 				cmd.Parent().Flags().Lookup("workflow-group").Value.String(),
 			)
 			if err != nil {
-				cmd.PrintErrln("== Failed To List All Artifacts From Workflow ==")
 				if strings.Contains(err.Error(), "the server responded with nothing") {
-					cmd.PrintErrln("No artifacts found for this workflow")
-					os.Exit(-1)
+					return clierrors.New(clierrors.NotFound, "No artifacts found for this workflow")
 				}
-				cmd.Println(err)
-				os.Exit(-1)
-			}
-
-			if opts.OutputJson {
-				cmd.Println(response)
+				return clierrors.Wrap(err, "Failed to list all artifacts from workflow")
 			}
 
-			cmd.Println(response)
+			return printer.Print(response, output.Artifacts)
 		},
 	}
-	listCmd.Flags().BoolVar(&opts.OutputJson, "output-json", false, "Output execution response as json to STDIN.")
 	return listCmd
 }
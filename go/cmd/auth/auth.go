@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/StackGuardian/sg-cli/cmd/auth/login"
+	"github.com/StackGuardian/sg-cli/cmd/auth/logout"
+	"github.com/StackGuardian/sg-cli/cmd/auth/status"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+)
+
+func NewAuthCmd(c *client.Client) *cobra.Command {
+	// authCmd represents the auth command
+	var authCmd = &cobra.Command{
+		Use:   "auth",
+		Short: "Manage API token credentials",
+		Long:  `Log in to Stackguardian platform with an API token, check the stored credentials and log out.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Println(`Sub-commands:
+  login       Validate and store an API token
+  status      Show the tenant, org access and token source
+  logout      Remove the stored API token`)
+		},
+	}
+
+	authCmd.AddCommand(login.NewLoginCmd(c))
+	authCmd.AddCommand(status.NewStatusCmd(c))
+	authCmd.AddCommand(logout.NewLogoutCmd())
+
+	return authCmd
+}
@@ -0,0 +1,151 @@
+package login
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/StackGuardian/sg-cli/auth"
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+type RunOptions struct {
+	Token          string
+	Org            string
+	Encrypt        bool
+	SkipValidation bool
+}
+
+func NewLoginCmd(c *client.Client) *cobra.Command {
+	opts := &RunOptions{}
+	// loginCmd represents the login command
+	var loginCmd = &cobra.Command{
+		Use:   "login",
+		Short: "Validate and store an API token",
+		Long: `Validate an API token against the organization and store it in the credentials file of the active profile.
+The token is read from --token, or from stdin when it is not a terminal, or prompted for.
+Use --encrypt to protect the stored token with a passphrase (SG_CREDENTIALS_PASSPHRASE is used when set).`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings := config.Current()
+
+			token, err := readToken(opts)
+			if err != nil {
+				return err
+			}
+			checked := *settings
+			checked.APIToken = token
+			checked.Sources = map[string]string{"api-token": "login input"}
+			if err := auth.CheckToken(&checked); err != nil {
+				return err
+			}
+
+			if !opts.SkipValidation {
+				org := opts.Org
+				if org == "" {
+					current, err := config.LoadContext(settings.ProfileName)
+					if err == nil && current.Org != "" {
+						org = current.Org
+					} else {
+						org = settings.Org
+					}
+				}
+				if org == "" {
+					return clierrors.New(clierrors.Usage, "An org is required to validate the API token. Pass --org or set a default with: sg-cli context set --org <org>")
+				}
+				organization, err := auth.Validate(cmd.Context(), c, token, org)
+				if err != nil {
+					return err
+				}
+				name := org
+				if organization != nil && organization.ResourceName != nil {
+					name = *organization.ResourceName
+				}
+				cmd.Println("API token is valid for org " + name + " on " + settings.APIURL + ".")
+			}
+
+			passphrase := ""
+			if opts.Encrypt {
+				passphrase, err = readNewPassphrase()
+				if err != nil {
+					return err
+				}
+			}
+			if err := auth.SaveCredential(settings.ProfileName, token, passphrase); err != nil {
+				return err
+			}
+
+			// Make sure the profile exists so it can be selected with --profile
+			file, err := config.Load()
+			if err == nil {
+				if _, ok := file.Profiles[settings.ProfileName]; !ok {
+					file.Profile(settings.ProfileName)
+					err = file.Save()
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			cmd.Println("Logged in with profile " + settings.ProfileName + ". Credentials stored in " + auth.CredentialsPath())
+			if strings.HasPrefix(settings.Sources["api-token"], "env") {
+				cmd.PrintErrln(">> [WARNING] SG_API_TOKEN is set and takes precedence over the stored credentials.")
+			}
+			return nil
+		},
+	}
+
+	loginCmd.Flags().StringVar(&opts.Token, "token", "", "The API token. Prefer stdin or the prompt to keep it out of the shell history.")
+	loginCmd.Flags().StringVar(&opts.Org, "org", "", "The organization used to validate the token. Defaults to the current context.")
+	loginCmd.Flags().BoolVar(&opts.Encrypt, "encrypt", false, "Encrypt the stored token with a passphrase.")
+	loginCmd.Flags().BoolVar(&opts.SkipValidation, "skip-validation", false, "Store the token without validating it against the API.")
+
+	return loginCmd
+}
+
+// readToken returns the token from the flag, stdin or a prompt
+func readToken(opts *RunOptions) (string, error) {
+	if opts.Token != "" {
+		return opts.Token, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read the API token from stdin: %w", err)
+		}
+		return strings.TrimSpace(line), nil
+	}
+	fmt.Fprint(os.Stderr, "API token: ")
+	token, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// readNewPassphrase returns SG_CREDENTIALS_PASSPHRASE or prompts twice for a new passphrase
+func readNewPassphrase() (string, error) {
+	passphrase, err := auth.Passphrase("New passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("the passphrase cannot be empty")
+	}
+	if os.Getenv("SG_CREDENTIALS_PASSPHRASE") != "" {
+		return passphrase, nil
+	}
+	confirmation, err := auth.Passphrase("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if confirmation != passphrase {
+		return "", fmt.Errorf("the passphrases do not match")
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,34 @@
+package logout
+
+import (
+	"github.com/StackGuardian/sg-cli/auth"
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewLogoutCmd() *cobra.Command {
+	// logoutCmd represents the logout command
+	var logoutCmd = &cobra.Command{
+		Use:   "logout",
+		Short: "Remove the stored API token",
+		Long:  `Remove the API token stored by "sg-cli auth login" for the active profile.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings := config.Current()
+			deleted, err := auth.DeleteCredential(settings.ProfileName)
+			if err != nil {
+				return err
+			}
+			if !deleted {
+				cmd.Println("No stored credentials for profile " + settings.ProfileName + ".")
+			} else {
+				cmd.Println("Logged out of profile " + settings.ProfileName + ".")
+			}
+			if settings.APIToken != "" {
+				cmd.PrintErrln(">> [WARNING] An API token is still configured from " + settings.Sources["api-token"] + ".")
+			}
+			return nil
+		},
+	}
+
+	return logoutCmd
+}
@@ -0,0 +1,61 @@
+package status
+
+import (
+	"github.com/StackGuardian/sg-cli/auth"
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+)
+
+type RunOptions struct {
+	Org string
+}
+
+func NewStatusCmd(c *client.Client) *cobra.Command {
+	opts := &RunOptions{}
+	// statusCmd represents the status command
+	var statusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show the tenant, org access and token source",
+		Long:  `Show the tenant the active profile points to, where the API token comes from and whether it has access to the org.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings := config.Current()
+			cmd.Println("Profile: " + settings.ProfileName)
+			cmd.Println("API URL: " + settings.APIURL + " (" + settings.Sources["api-url"] + ")")
+			cmd.Println("Dashboard URL: " + settings.DashboardURL + " (" + settings.Sources["dashboard-url"] + ")")
+
+			if err := auth.LoadToken(settings); err != nil {
+				return err
+			}
+			if err := auth.CheckToken(settings); err != nil {
+				cmd.Println("Token: <none>")
+				return err
+			}
+			cmd.Println("Token: " + auth.MaskToken(settings.APIToken) + " (" + settings.Sources["api-token"] + ")")
+
+			org := opts.Org
+			if org == "" {
+				current, err := config.LoadContext(settings.ProfileName)
+				if err == nil && current.Org != "" {
+					org = current.Org
+				} else {
+					org = settings.Org
+				}
+			}
+			if org == "" {
+				cmd.Println("Org access: unknown, pass --org or set a context to check it")
+				return nil
+			}
+			if _, err := auth.Validate(cmd.Context(), c, settings.APIToken, org); err != nil {
+				cmd.Println("Org access: " + org + " (denied)")
+				return err
+			}
+			cmd.Println("Org access: " + org + " (ok)")
+			return nil
+		},
+	}
+
+	statusCmd.Flags().StringVar(&opts.Org, "org", "", "The organization to check access for. Defaults to the current context.")
+
+	return statusCmd
+}
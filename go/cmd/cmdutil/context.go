@@ -0,0 +1,75 @@
+package cmdutil
+
+import (
+	"fmt"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/spf13/cobra"
+)
+
+// ResolveContext is used as PersistentPreRunE by the commands that operate inside a workflow group.
+// When --org or --workflow-group are not passed, they are filled from the current context
+// (see `sg-cli context`) and then from the active profile or environment.
+// The resolved values are printed to stderr so it is always visible what a command runs against.
+func ResolveContext(cmd *cobra.Command, args []string) error {
+	return ResolveFlags(cmd, "org", "workflow-group")
+}
+
+// ResolveFlags fills the given flags (org and/or workflow-group) the same way as ResolveContext.
+// Flags that the command does not define are ignored.
+func ResolveFlags(cmd *cobra.Command, names ...string) error {
+	settings := config.Current()
+	current, err := config.LoadContext(settings.ProfileName)
+	if err != nil {
+		return err
+	}
+
+	fallbacks := []struct {
+		flag        string
+		fromContext string
+		fromConfig  string
+	}{
+		{"org", current.Org, settings.Org},
+		{"workflow-group", current.WorkflowGroup, settings.WorkflowGroup},
+	}
+
+	resolved := ""
+	for _, fallback := range fallbacks {
+		flag := cmd.Flags().Lookup(fallback.flag)
+		if flag == nil || !contains(names, fallback.flag) {
+			continue
+		}
+		source := "--" + fallback.flag
+		if !flag.Changed {
+			switch {
+			case fallback.fromContext != "":
+				source = "context"
+				err = cmd.Flags().Set(fallback.flag, fallback.fromContext)
+			case fallback.fromConfig != "":
+				source = settings.Sources[fallback.flag]
+				err = cmd.Flags().Set(fallback.flag, fallback.fromConfig)
+			default:
+				return clierrors.New(clierrors.Usage, "required flag(s) \"%s\" not set. Pass --%s or set a default with: sg-cli context set --%s <value>",
+					fallback.flag, fallback.flag, fallback.flag)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		resolved += fmt.Sprintf(" %s=%s (%s)", fallback.flag, flag.Value.String(), source)
+	}
+	if resolved != "" {
+		cmd.PrintErrln(">> Using" + resolved)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
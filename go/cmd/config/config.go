@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/StackGuardian/sg-cli/cmd/config/get"
+	"github.com/StackGuardian/sg-cli/cmd/config/list"
+	"github.com/StackGuardian/sg-cli/cmd/config/set"
+	"github.com/StackGuardian/sg-cli/cmd/config/useprofile"
+	"github.com/spf13/cobra"
+)
+
+func NewConfigCmd() *cobra.Command {
+	// configCmd represents the config command
+	var configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Manage sg-cli configuration profiles",
+		Long: `Manage named configuration profiles stored in the sg-cli config file.
+Environment variables (SG_API_TOKEN, SG_BASE_URL, SG_DASHBOARD_URL, SG_ORG, SG_WORKFLOW_GROUP) override the values from the file.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Println(`Sub-commands:
+  get           Get a value from the active profile
+  set           Set a value in the active profile
+  list          List all profiles
+  use-profile   Switch the profile used by default`)
+		},
+	}
+
+	configCmd.AddCommand(get.NewGetCmd())
+	configCmd.AddCommand(set.NewSetCmd())
+	configCmd.AddCommand(list.NewListCmd())
+	configCmd.AddCommand(useprofile.NewUseProfileCmd())
+
+	return configCmd
+}
@@ -0,0 +1,39 @@
+package get
+
+import (
+	"strings"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	sgconfig "github.com/StackGuardian/sg-cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewGetCmd() *cobra.Command {
+	// getCmd represents the get command
+	var getCmd = &cobra.Command{
+		Use:   "get <key>",
+		Short: "Get a value from the active profile",
+		Long: `Get a value from the active profile. Valid keys: ` + strings.Join(sgconfig.Keys(), ", ") + `.
+Use the global --profile flag to read from another profile.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := sgconfig.Load()
+			if err != nil {
+				return err
+			}
+			profileName := sgconfig.Current().ProfileName
+			profile, ok := file.Profiles[profileName]
+			if !ok {
+				return clierrors.New(clierrors.NotFound, "Profile %s does not exist.", profileName)
+			}
+			value, err := profile.Get(args[0])
+			if err != nil {
+				return err
+			}
+			cmd.Println(value)
+			return nil
+		},
+	}
+
+	return getCmd
+}
@@ -0,0 +1,48 @@
+package list
+
+import (
+	"github.com/StackGuardian/sg-cli/auth"
+	sgconfig "github.com/StackGuardian/sg-cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewListCmd() *cobra.Command {
+	// listCmd represents the list command
+	var listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List all profiles",
+		Long:  `List all profiles from the config file. The active profile is marked with "*", API tokens are masked.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := sgconfig.Load()
+			if err != nil {
+				return err
+			}
+			if len(file.Profiles) == 0 {
+				cmd.Println("No profiles found in " + sgconfig.Path())
+				return nil
+			}
+			active := sgconfig.Current().ProfileName
+			for _, name := range file.ProfileNames() {
+				marker := " "
+				if name == active {
+					marker = "*"
+				}
+				cmd.Println(marker + " " + name)
+				profile := file.Profiles[name]
+				for _, key := range sgconfig.Keys() {
+					value, _ := profile.Get(key)
+					if value == "" {
+						continue
+					}
+					if key == "api-token" {
+						value = auth.MaskToken(value)
+					}
+					cmd.Println("    " + key + ": " + value)
+				}
+			}
+			return nil
+		},
+	}
+
+	return listCmd
+}
@@ -0,0 +1,37 @@
+package set
+
+import (
+	"strings"
+
+	sgconfig "github.com/StackGuardian/sg-cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewSetCmd() *cobra.Command {
+	// setCmd represents the set command
+	var setCmd = &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a value in the active profile",
+		Long: `Set a value in the active profile, the profile is created if it does not exist.
+Valid keys: ` + strings.Join(sgconfig.Keys(), ", ") + `.
+Use the global --profile flag to write to another profile.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := sgconfig.Load()
+			if err != nil {
+				return err
+			}
+			profileName := sgconfig.Current().ProfileName
+			if err := file.Profile(profileName).Set(args[0], args[1]); err != nil {
+				return err
+			}
+			if err := file.Save(); err != nil {
+				return err
+			}
+			cmd.Println("Updated " + args[0] + " in profile " + profileName + ".")
+			return nil
+		},
+	}
+
+	return setCmd
+}
@@ -0,0 +1,34 @@
+package useprofile
+
+import (
+	"github.com/StackGuardian/sg-cli/clierrors"
+	sgconfig "github.com/StackGuardian/sg-cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewUseProfileCmd() *cobra.Command {
+	// useProfileCmd represents the use-profile command
+	var useProfileCmd = &cobra.Command{
+		Use:   "use-profile <name>",
+		Short: "Switch the profile used by default",
+		Long:  `Switch the profile used by default when neither --profile nor SG_PROFILE is set.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := sgconfig.Load()
+			if err != nil {
+				return err
+			}
+			if _, ok := file.Profiles[args[0]]; !ok {
+				return clierrors.New(clierrors.NotFound, "Profile %s does not exist. Create it with: sg-cli config set --profile %s api-token <token>", args[0], args[0])
+			}
+			file.CurrentProfile = args[0]
+			if err := file.Save(); err != nil {
+				return err
+			}
+			cmd.Println("Switched to profile " + args[0] + ".")
+			return nil
+		},
+	}
+
+	return useProfileCmd
+}
@@ -0,0 +1,25 @@
+package clear
+
+import (
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewClearCmd() *cobra.Command {
+	// clearCmd represents the clear command
+	var clearCmd = &cobra.Command{
+		Use:   "clear",
+		Short: "Clear the current org and workflow group",
+		Long:  `Clear the current org and workflow group of the active profile.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profileName := config.Current().ProfileName
+			if err := config.ClearContext(profileName); err != nil {
+				return err
+			}
+			cmd.Println("Context cleared for profile " + profileName + ".")
+			return nil
+		},
+	}
+
+	return clearCmd
+}
@@ -0,0 +1,33 @@
+package context
+
+import (
+	"fmt"
+
+	"github.com/StackGuardian/sg-cli/cmd/context/clear"
+	"github.com/StackGuardian/sg-cli/cmd/context/set"
+	"github.com/StackGuardian/sg-cli/cmd/context/show"
+	"github.com/spf13/cobra"
+)
+
+func NewContextCmd() *cobra.Command {
+	// contextCmd represents the context command
+	var contextCmd = &cobra.Command{
+		Use:   "context",
+		Short: "Manage the current org and workflow group",
+		Long: `Manage the current org and workflow group.
+The workflow, stack and artifacts commands use them when --org or --workflow-group are not passed.
+The context is stored per profile.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Println(`Sub-commands:
+  set       Set the current org and/or workflow group
+  show      Show the current org and workflow group
+  clear     Clear the current org and workflow group`)
+		},
+	}
+
+	contextCmd.AddCommand(set.NewSetCmd())
+	contextCmd.AddCommand(show.NewShowCmd())
+	contextCmd.AddCommand(clear.NewClearCmd())
+
+	return contextCmd
+}
@@ -0,0 +1,50 @@
+package set
+
+import (
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/spf13/cobra"
+)
+
+type RunOptions struct {
+	Org    string
+	WfgGrp string
+}
+
+func NewSetCmd() *cobra.Command {
+	opts := &RunOptions{}
+	// setCmd represents the set command
+	var setCmd = &cobra.Command{
+		Use:   "set",
+		Short: "Set the current org and/or workflow group",
+		Long:  `Set the current org and/or workflow group for the active profile.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Org == "" && opts.WfgGrp == "" {
+				return clierrors.New(clierrors.Usage, "At least one of --org or --workflow-group is required.")
+			}
+			profileName := config.Current().ProfileName
+			current, err := config.LoadContext(profileName)
+			if err != nil {
+				return err
+			}
+			if opts.Org != "" {
+				current.Org = opts.Org
+			}
+			if opts.WfgGrp != "" {
+				current.WorkflowGroup = opts.WfgGrp
+			}
+			if err := config.SaveContext(profileName, current); err != nil {
+				return err
+			}
+			cmd.Println("Context updated for profile " + profileName + ".")
+			cmd.Println("Org: " + current.Org)
+			cmd.Println("Workflow group: " + current.WorkflowGroup)
+			return nil
+		},
+	}
+
+	setCmd.Flags().StringVar(&opts.Org, "org", "", "The organization name on Stackguardian platform.")
+	setCmd.Flags().StringVar(&opts.WfgGrp, "workflow-group", "", "The workflow group under the organization.")
+
+	return setCmd
+}
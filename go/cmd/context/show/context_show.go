@@ -0,0 +1,39 @@
+package show
+
+import (
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewShowCmd() *cobra.Command {
+	// showCmd represents the show command
+	var showCmd = &cobra.Command{
+		Use:   "show",
+		Short: "Show the current org and workflow group",
+		Long:  `Show the org and workflow group used when --org and --workflow-group are not passed, and where they come from.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings := config.Current()
+			current, err := config.LoadContext(settings.ProfileName)
+			if err != nil {
+				return err
+			}
+			cmd.Println("Profile: " + settings.ProfileName)
+			cmd.Println("Org: " + describe(current.Org, settings.Org, settings.Sources["org"]))
+			cmd.Println("Workflow group: " + describe(current.WorkflowGroup, settings.WorkflowGroup, settings.Sources["workflow-group"]))
+			return nil
+		},
+	}
+
+	return showCmd
+}
+
+// describe returns the effective value and its source
+func describe(fromContext string, fromConfig string, configSource string) string {
+	if fromContext != "" {
+		return fromContext + " (context)"
+	}
+	if fromConfig != "" {
+		return fromConfig + " (" + configSource + ")"
+	}
+	return "<not set>"
+}
@@ -0,0 +1,41 @@
+package open
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// browserCommand returns the command that opens a link in the browser, or nil when there is none
+func browserCommand(link string) *exec.Cmd {
+	if browser := os.Getenv("BROWSER"); browser != "" {
+		parts := strings.Fields(browser)
+		return exec.Command(parts[0], append(parts[1:], link)...)
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", link)
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+	default:
+		// Headless machines (CI, SSH sessions) have no display to open a browser on
+		if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+			return nil
+		}
+		if _, err := exec.LookPath("xdg-open"); err != nil {
+			return nil
+		}
+		return exec.Command("xdg-open", link)
+	}
+}
+
+// openBrowser opens the link without waiting for the browser to exit.
+// It does nothing when no browser is available.
+func openBrowser(link string) error {
+	browser := browserCommand(link)
+	if browser == nil {
+		return nil
+	}
+	return browser.Start()
+}
@@ -0,0 +1,155 @@
+package open
+
+import (
+	"github.com/StackGuardian/sg-cli/cmd/cmdutil"
+	"github.com/StackGuardian/sg-cli/dashboard"
+	"github.com/spf13/cobra"
+)
+
+type RunOptions struct {
+	Org       string
+	WfgGrp    string
+	NoBrowser bool
+}
+
+// resource describes a page on the dashboard that `sg-cli open` can link to
+type resource struct {
+	use   string
+	short string
+	args  cobra.PositionalArgs
+	// flags are the context flags the link needs, resolved like in the workflow and stack commands
+	flags []string
+	link  func(l *dashboard.Links, opts *RunOptions, args []string) string
+}
+
+var resources = []resource{
+	{
+		use:   "org",
+		short: "Open the organization",
+		args:  cobra.NoArgs,
+		flags: []string{"org"},
+		link: func(l *dashboard.Links, opts *RunOptions, args []string) string {
+			return l.Org(opts.Org)
+		},
+	},
+	{
+		use:   "workflow-group",
+		short: "Open the workflow group",
+		args:  cobra.NoArgs,
+		flags: []string{"org", "workflow-group"},
+		link: func(l *dashboard.Links, opts *RunOptions, args []string) string {
+			return l.WorkflowGroup(opts.Org, opts.WfgGrp)
+		},
+	},
+	{
+		use:   "workflow <workflow-id>",
+		short: "Open the runs of a workflow",
+		args:  cobra.ExactArgs(1),
+		flags: []string{"org", "workflow-group"},
+		link: func(l *dashboard.Links, opts *RunOptions, args []string) string {
+			return l.WorkflowRuns(opts.Org, opts.WfgGrp, args[0])
+		},
+	},
+	{
+		use:   "workflow-run <workflow-id> <workflow-run-id>",
+		short: "Open a workflow run",
+		args:  cobra.ExactArgs(2),
+		flags: []string{"org", "workflow-group"},
+		link: func(l *dashboard.Links, opts *RunOptions, args []string) string {
+			return l.WorkflowRun(opts.Org, opts.WfgGrp, args[0], args[1])
+		},
+	},
+	{
+		use:   "artifacts <workflow-id>",
+		short: "Open the artifacts of a workflow",
+		args:  cobra.ExactArgs(1),
+		flags: []string{"org", "workflow-group"},
+		link: func(l *dashboard.Links, opts *RunOptions, args []string) string {
+			return l.Artifacts(opts.Org, opts.WfgGrp, args[0])
+		},
+	},
+	{
+		use:   "stack <stack-id>",
+		short: "Open the runs of a stack",
+		args:  cobra.ExactArgs(1),
+		flags: []string{"org", "workflow-group"},
+		link: func(l *dashboard.Links, opts *RunOptions, args []string) string {
+			return l.StackRuns(opts.Org, opts.WfgGrp, args[0])
+		},
+	},
+	{
+		use:   "stack-run <stack-id> <stack-run-id>",
+		short: "Open a stack run",
+		args:  cobra.ExactArgs(2),
+		flags: []string{"org", "workflow-group"},
+		link: func(l *dashboard.Links, opts *RunOptions, args []string) string {
+			return l.StackRun(opts.Org, opts.WfgGrp, args[0], args[1])
+		},
+	},
+	{
+		use:   "integrations [integration-name]",
+		short: "Open the integrations of the organization",
+		args:  cobra.MaximumNArgs(1),
+		flags: []string{"org"},
+		link: func(l *dashboard.Links, opts *RunOptions, args []string) string {
+			name := ""
+			if len(args) == 1 {
+				name = args[0]
+			}
+			return l.Integrations(opts.Org, name)
+		},
+	},
+	{
+		use:   "settings [tab]",
+		short: "Open the organization settings, for example: sg-cli open settings api_key",
+		args:  cobra.MaximumNArgs(1),
+		flags: []string{"org"},
+		link: func(l *dashboard.Links, opts *RunOptions, args []string) string {
+			tab := ""
+			if len(args) == 1 {
+				tab = args[0]
+			}
+			return l.Settings(opts.Org, tab)
+		},
+	},
+}
+
+func NewOpenCmd() *cobra.Command {
+	opts := &RunOptions{}
+
+	// openCmd represents the open command
+	var openCmd = &cobra.Command{
+		Use:   "open",
+		Short: "Open a resource on the Stackguardian dashboard",
+		Long: `Print the dashboard link of a resource and open it in the browser when one is available.
+Links are built from dashboard-url in the config file or SG_DASHBOARD_URL.`,
+	}
+
+	openCmd.PersistentFlags().StringVar(&opts.Org, "org", "", "The organization name on Stackguardian platform. Defaults to the current context.")
+	openCmd.PersistentFlags().StringVar(&opts.WfgGrp, "workflow-group", "", "The workflow group under the organization. Defaults to the current context.")
+	openCmd.PersistentFlags().BoolVar(&opts.NoBrowser, "no-browser", false, "Only print the link, do not open a browser.")
+
+	for _, r := range resources {
+		r := r
+		openCmd.AddCommand(&cobra.Command{
+			Use:   r.use,
+			Short: r.short,
+			Args:  r.args,
+			PreRunE: func(cmd *cobra.Command, args []string) error {
+				return cmdutil.ResolveFlags(cmd, r.flags...)
+			},
+			Run: func(cmd *cobra.Command, args []string) {
+				link := r.link(dashboard.Default(), opts, args)
+				cmd.Println(link)
+				if opts.NoBrowser {
+					return
+				}
+				if err := openBrowser(link); err != nil {
+					cmd.PrintErrln(">> Could not open the browser: " + err.Error())
+				}
+			},
+		})
+	}
+
+	return openCmd
+}
@@ -1,16 +1,36 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/StackGuardian/sg-cli/auth"
+	"github.com/StackGuardian/sg-cli/clierrors"
 	"github.com/StackGuardian/sg-cli/cmd/artifacts"
+	authcmd "github.com/StackGuardian/sg-cli/cmd/auth"
+	configcmd "github.com/StackGuardian/sg-cli/cmd/config"
+	contextcmd "github.com/StackGuardian/sg-cli/cmd/context"
+	opencmd "github.com/StackGuardian/sg-cli/cmd/open"
 	"github.com/StackGuardian/sg-cli/cmd/stack"
 	workflow "github.com/StackGuardian/sg-cli/cmd/workflow"
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-cli/transport"
 	"github.com/StackGuardian/sg-sdk-go/client"
 	"github.com/StackGuardian/sg-sdk-go/option"
 	"github.com/spf13/cobra"
 )
 
+// localAnnotation marks commands that manage local state and do not require stored credentials
+const localAnnotation = "sg-cli/local"
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:     "sg-cli",
@@ -18,28 +38,215 @@ var rootCmd = &cobra.Command{
 	Short:   "sg-cli is CLI command for managing resources on Stackguardian platform.",
 	Long: `sg-cli is CLI command for managing resources on Stackguardian platform.
 More information available at: https://docs.qa.stackguardian.io/docs/`,
+	// Errors are printed by Execute, see printError
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
+// cancelTimeout releases the context of --timeout
+var cancelTimeout context.CancelFunc = func() {}
+
+// harFile is the path of the HAR file written when the command finishes, see --har-file
+var harFile string
+
 // Execute adds all child commands to the root command and sets flags appropriately.
+// The command runs with a context that is cancelled on SIGINT or SIGTERM and by --timeout.
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer func() { cancelTimeout() }()
+
+	executed, err := rootCmd.ExecuteContextC(ctx)
+	if harFile != "" {
+		if harErr := transport.WriteHAR(harFile, rootCmd.Version); harErr != nil {
+			rootCmd.PrintErrln(">> [ERROR] Failed to write HAR file: " + harErr.Error())
+		}
+	}
+	if executed == nil {
+		executed = rootCmd
+	}
+	// A cancelled command fails, even if it did not notice the cancellation itself
+	if err == nil && executed.Context() != nil {
+		err = executed.Context().Err()
+	}
 	if err != nil {
-		os.Exit(1)
+		os.Exit(printError(executed, err))
 	}
 }
 
+// usageErrors are the prefixes of the errors cobra returns for invalid command lines
+var usageErrors = []string{"unknown command", "unknown flag", "unknown shorthand flag", "required flag(s)",
+	"flag needs an argument", "invalid argument", "accepts ", "requires at least", "requires at most"}
+
+// printError prints err to stderr and returns the exit code of its category, see clierrors.
+// When JSON output was requested, the error is printed as a JSON envelope instead.
+func printError(cmd *cobra.Command, err error) int {
+	classified := clierrors.From(err)
+	if classified.Category == clierrors.Changes || classified.Category == clierrors.Drift {
+		return classified.ExitCode()
+	}
+	if classified.Category == clierrors.General {
+		for _, prefix := range usageErrors {
+			if strings.HasPrefix(err.Error(), prefix) {
+				classified.Category = clierrors.Usage
+				break
+			}
+		}
+	}
+	if detailedExitCode(cmd) {
+		classified = clierrors.Detailed(classified)
+	}
+	if ctx := cmd.Context(); ctx != nil {
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			classified.Category = clierrors.Timeout
+		case errors.Is(ctx.Err(), context.Canceled):
+			classified.Category = clierrors.Cancelled
+		}
+	}
+
+	if jsonOutput(cmd) {
+		if envelopeErr := clierrors.WriteEnvelope(cmd.ErrOrStderr(), classified); envelopeErr == nil {
+			return classified.ExitCode()
+		}
+	}
+	cmd.PrintErrln("Error: " + classified.Error())
+	switch classified.Category {
+	case clierrors.Usage:
+		cmd.PrintErrln("Run '" + cmd.CommandPath() + " --help' for usage.")
+	case clierrors.Timeout:
+		cmd.PrintErrln(">> [ERROR] The operation did not finish within --timeout and was cancelled.")
+	case clierrors.Cancelled:
+		cmd.PrintErrln(">> Interrupted, the operation was cancelled.")
+	}
+	return classified.ExitCode()
+}
+
+// detailedExitCode reports whether the command was run with --detailed-exitcode
+func detailedExitCode(cmd *cobra.Command) bool {
+	flag := cmd.Flags().Lookup("detailed-exitcode")
+	return flag != nil && flag.Value.String() == "true"
+}
+
+// jsonOutput reports whether the command was asked for JSON output
+func jsonOutput(cmd *cobra.Command) bool {
+	printer, err := output.New(cmd)
+	return err == nil && printer.JSON()
+}
+
 func init() {
+	// Run the persistent pre-run hooks of every parent, not only the closest one
+	cobra.EnableTraverseRunHooks = true
+
+	var profile string
+	var timeout time.Duration
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Cancel the command when it runs longer than this, e.g. 10m. No timeout by default.")
 
-	API_KEY := "apikey " + os.Getenv("SG_API_TOKEN")
-	SG_BASE_URL := os.Getenv("SG_BASE_URL")
-	if SG_BASE_URL == "" {
-		SG_BASE_URL = "https://api.app.stackguardian.io"
+	var verbose bool
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Print additional information, such as retried requests, to stderr.")
+	var debug bool
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Print every HTTP request and response to stderr, with secrets redacted. Can also be enabled with SG_DEBUG=1.")
+	rootCmd.PersistentFlags().StringVar(&harFile, "har-file", "", "Record every HTTP request and response, with secrets redacted, to this HAR file.")
+	output.AddFlags(rootCmd)
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "The configuration profile to use. Overrides SG_PROFILE and the current profile from the config file.")
+
+	// Connection flags override the config keys of the same name
+	connection := map[string]*string{}
+	for _, flag := range []struct{ name, usage string }{
+		{"ca-file", "PEM encoded CA bundle to trust in addition to the system roots, e.g. of a TLS-inspecting proxy."},
+		{"client-cert", "PEM encoded client certificate for mutual TLS. Requires --client-key."},
+		{"client-key", "PEM encoded private key of the client certificate."},
+		{"proxy", "HTTP(S) proxy URL. Defaults to HTTPS_PROXY/HTTP_PROXY from the environment."},
+		{"request-timeout", "Timeout of a single HTTP request including its retries, e.g. 30s or 2m. No timeout by default."},
+		{"max-attempts", "Maximum number of attempts of a request that failed with a transient error. Defaults to 4, 1 disables retries."},
+	} {
+		connection[flag.name] = rootCmd.PersistentFlags().String(flag.name, "", flag.usage)
 	}
-	c := client.NewClient(
-		option.WithApiKey(API_KEY),
-		option.WithBaseURL(SG_BASE_URL),
-	)
+
+	// The client is populated once the flags are parsed, see PersistentPreRunE.
+	// Sub-commands keep the pointer and only use it when they run.
+	c := &client.Client{}
+
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if timeout > 0 {
+			var ctx context.Context
+			ctx, cancelTimeout = context.WithTimeout(cmd.Context(), timeout)
+			cmd.SetContext(ctx)
+		}
+		settings, err := config.Resolve(profile)
+		if err != nil {
+			return err
+		}
+		for key, value := range connection {
+			if err := settings.Override(key, *value, "--"+key); err != nil {
+				return err
+			}
+		}
+		config.SetCurrent(settings)
+		if !isLocal(cmd) {
+			if !settings.ProfileExists && settings.ProfileName != config.DefaultProfile {
+				return clierrors.New(clierrors.Usage, "profile %q does not exist in %s. Create it with: sg-cli auth login --profile %s",
+					settings.ProfileName, config.Path(), settings.ProfileName)
+			}
+			// Fail fast with instructions instead of a 401 from the API
+			if err := auth.LoadToken(settings); err != nil {
+				return err
+			}
+			if err := auth.CheckToken(settings); err != nil {
+				return err
+			}
+		}
+		if verbose || debug || debugFromEnv() {
+			transport.Log = cmd.ErrOrStderr()
+		}
+		if debug || debugFromEnv() {
+			transport.DebugLog = cmd.ErrOrStderr()
+		}
+		if harFile != "" {
+			transport.RecordHAR()
+		}
+		httpClient, err := transport.New(settings)
+		if err != nil {
+			return err
+		}
+		transport.SetClient(httpClient)
+		*c = *newClient(settings, httpClient)
+		return nil
+	}
+
 	rootCmd.AddCommand(workflow.NewWorkflowCmd(c))
 	rootCmd.AddCommand(stack.NewStackCmd(c))
 	rootCmd.AddCommand(artifacts.NewArtifactsCmd(c))
+
+	for _, localCmd := range []*cobra.Command{configcmd.NewConfigCmd(), contextcmd.NewContextCmd(), authcmd.NewAuthCmd(c), opencmd.NewOpenCmd()} {
+		localCmd.Annotations = map[string]string{localAnnotation: "true"}
+		rootCmd.AddCommand(localCmd)
+	}
+}
+
+// newClient builds the Stackguardian SDK client from the resolved settings
+func newClient(settings *config.Settings, httpClient *http.Client) *client.Client {
+	return client.NewClient(
+		option.WithApiKey("apikey "+settings.APIToken),
+		option.WithBaseURL(settings.APIURL),
+		option.WithHTTPClient(httpClient),
+		// Retries are handled by the shared transport
+		option.WithMaxAttempts(1),
+	)
+}
+
+// debugFromEnv reports whether SG_DEBUG enables debug mode
+func debugFromEnv() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("SG_DEBUG"))
+	return enabled
+}
+
+// isLocal reports whether the command or any of its parents is annotated as local
+func isLocal(cmd *cobra.Command) bool {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd.Annotations[localAnnotation] == "true" {
+			return true
+		}
+	}
+	return false
 }
@@ -1,35 +1,46 @@
 package apply
 
 import (
-	"context"
-	"os"
-
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/cmd/stack/runs/watch"
+	"github.com/StackGuardian/sg-cli/dashboard"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-cli/runs"
 	sggosdk "github.com/StackGuardian/sg-sdk-go"
 	"github.com/StackGuardian/sg-sdk-go/client"
 	"github.com/spf13/cobra"
 )
 
 type RunOptions struct {
-	OutputJson bool
-	Org        string
-	WfgGrp     string
-	Stack      string
+	watch.Options
+	Org    string
+	WfgGrp string
+	Stack  string
+	Wait   bool
+	Guard  runs.GuardOptions
 }
 
 func NewApplyCmd(c *client.Client) *cobra.Command {
 	opts := &RunOptions{}
-	DASHBOARD_URL := "https://app.stackguardian.io/orchestrator"
 	// applyCmd represents the apply command
 	var applyCmd = &cobra.Command{
 		Use:   "apply",
 		Short: "Execute \"Apply\" on existing Stack",
 		Long:  `Execute "Apply" on existing Stack`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
 			opts.Org = cmd.Parent().PersistentFlags().Lookup("org").Value.String()
 			opts.WfgGrp = cmd.Parent().PersistentFlags().Lookup("workflow-group").Value.String()
 			opts.Stack = cmd.Flags().Lookup("stack-id").Value.String()
+			if err := opts.Guard.Guard(cmd.Context(), "Stack "+opts.Stack, watch.ActiveRuns(c, opts.Org, opts.WfgGrp, opts.Stack),
+				watch.CancelRun(c, opts.Org, opts.WfgGrp, opts.Stack), opts.Interval, printer.Println); err != nil {
+				return err
+			}
 			response, err := c.StackRuns.CreateStackRun(
-				context.Background(),
+				cmd.Context(),
 				opts.Org,
 				opts.Stack,
 				opts.WfgGrp,
@@ -38,30 +49,33 @@ func NewApplyCmd(c *client.Client) *cobra.Command {
 				},
 			)
 			if err != nil {
-				cmd.Println(err)
-				os.Exit(-1)
+				return clierrors.Wrap(err, "Failed to apply stack %s", opts.Stack)
+			}
+			if printer.Structured() && !opts.Wait {
+				if err := printer.Print(response, output.StackRun); err != nil {
+					return err
+				}
 			}
-			if opts.OutputJson {
-				cmd.Println(response)
+			stackRunPath := dashboard.Default().StackRuns(opts.Org, opts.WfgGrp, opts.Stack)
+			printer.Println("To view the Stack run, please visit the following URL:")
+			printer.Println(stackRunPath)
+			printer.Println("Stack apply executed.")
+			if opts.Wait {
+				stackRun, err := watch.StackRunID(response)
+				if err != nil {
+					return err
+				}
+				return watch.ForStackRun(cmd.Context(), c, printer, opts.Org, opts.WfgGrp, opts.Stack, stackRun, &opts.Options)
 			}
-			workflowRunPath := DASHBOARD_URL +
-				"/orgs/" +
-				opts.Org +
-				"/wfgrps/" +
-				opts.WfgGrp +
-				"/stacks/" +
-				opts.Stack +
-				"?tab=runs"
-			cmd.Println("To view the Stack run, please visit the following URL:")
-			cmd.Println(workflowRunPath)
-			cmd.Println("Stack apply executed.")
+			return nil
 		},
 	}
 
 	applyCmd.Flags().String("stack-id", "", "The stack ID to retrieve.")
 	applyCmd.MarkFlagRequired("stack-id")
-
-	applyCmd.Flags().BoolVar(&opts.OutputJson, "output-json", false, "Output execution response as json to STDIN.")
+	applyCmd.Flags().BoolVar(&opts.Wait, "wait", false, "Wait for the stack run to finish and print the tree of its workflow runs. The exit code reflects its outcome.")
+	watch.AddFlags(applyCmd, &opts.Options)
+	runs.AddGuardFlags(applyCmd, &opts.Guard)
 
 	return applyCmd
 }
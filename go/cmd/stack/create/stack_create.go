@@ -1,12 +1,13 @@
 package create
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"os"
 	"strings"
 
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/output"
 	"github.com/StackGuardian/sg-cli/utilities"
 	sggosdk "github.com/StackGuardian/sg-sdk-go"
 	"github.com/StackGuardian/sg-sdk-go/client"
@@ -19,7 +20,6 @@ type RunOptions struct {
 	Preview      bool
 	DryRun       bool
 	Run          bool
-	OutputJson   bool
 	PatchPayload string
 	Payload      string
 }
@@ -32,14 +32,18 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 		Short: "Create new stack",
 		Long:  `Create new stack in the specified organization and workflow group.`,
 		Args:  cobra.ExactArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
 			opts.Org = cmd.Parent().PersistentFlags().Lookup("org").Value.String()
 			opts.WfgGrp = cmd.Parent().PersistentFlags().Lookup("workflow-group").Value.String()
 			opts.Payload = args[0]
 
 			payload, err := os.ReadFile(opts.Payload)
 			if err != nil {
-				cmd.PrintErrln(err)
+				return clierrors.Wrap(err, "Failed to read Stack payload")
 			}
 
 			var createStackRequest *sggosdk.Stack
@@ -50,16 +54,14 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 					),
 					&createStackRequest)
 				if err != nil {
-					cmd.Printf("Error during patching Stack payload: %s\n", err)
-					os.Exit(-1)
+					return &clierrors.Error{Category: clierrors.Validation, Message: "Error during patching Stack payload", Err: err}
 				}
 			} else {
 				err := json.Unmarshal(
 					payload,
 					&createStackRequest)
 				if err != nil {
-					cmd.Printf("Error while unmarshalling Stack payload: %s\n", err)
-					os.Exit(-1)
+					return &clierrors.Error{Category: clierrors.Validation, Message: "Error while unmarshalling Stack payload", Err: err}
 				}
 			}
 			//Run on create
@@ -70,31 +72,32 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 			}
 
 			// Perform actions based on the set flags
-			if err := performPreExecutionFlagChecks(cmd, createStackRequest, opts); err != nil {
-				cmd.PrintErrln(err)
-				os.Exit(-1)
+			if err := performPreExecutionFlagChecks(printer, createStackRequest, opts); err != nil {
+				if errors.Is(err, errDryRun) {
+					return nil
+				}
+				return err
 			}
 
 			response, err := c.Stacks.CreateStack(
-				context.Background(),
+				cmd.Context(),
 				opts.Org,
 				opts.WfgGrp,
 				createStackRequest,
 			)
 			if err != nil {
 				if strings.Contains(err.Error(), "cannot unmarshal") {
-					cmd.Println("Stack was created successfully but an error occured while reading the response JSON.")
-					os.Exit(-1)
+					return clierrors.New(clierrors.General, "Stack was created successfully but an error occured while reading the response JSON.")
 				}
-				cmd.PrintErrln("== Failed To Create Stack ==")
-				cmd.PrintErrln(err)
-				os.Exit(-1)
+				return clierrors.Wrap(err, "Failed to create Stack")
 			}
-			if opts.OutputJson {
-				cmd.Println(response)
+			if printer.Structured() {
+				if err := printer.Print(response, output.Created); err != nil {
+					return err
+				}
 			}
-			cmd.Println("Stack created successfully.")
-
+			printer.Println("Stack created successfully.")
+			return nil
 		},
 	}
 
@@ -102,8 +105,6 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 
 	createCmd.Flags().StringVar(&opts.PatchPayload, "patch-payload", "", "Patch original payload.json input. Add or replace values. Requires valid JSON input.")
 
-	createCmd.Flags().BoolVar(&opts.OutputJson, "output-json", false, "Output execution response as json to STDIN.")
-
 	createCmd.Flags().BoolVar(&opts.Preview, "preview", false, "Preview payload content before creating. Execution will not pause.")
 
 	createCmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Similar to --preview. But execution will stop, nothing will be created.")
@@ -113,28 +114,25 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 	return createCmd
 }
 
+// errDryRun stops the command after the payload has been printed
+var errDryRun = errors.New("dry run")
+
 // performPreExecutionFlagChecks performs pre-execution flag checks and returns the payload
-func performPreExecutionFlagChecks(cmd *cobra.Command, payload *sggosdk.Stack, opts *RunOptions) error {
+func performPreExecutionFlagChecks(printer *output.Printer, payload *sggosdk.Stack, opts *RunOptions) error {
 
 	if payload.ResourceName == nil || payload.ResourceName.Value == "" {
-		return errors.New(">> [ERROR] Stack ResourceName is required in object payload, skipping")
+		return clierrors.New(clierrors.Validation, ">> [ERROR] Stack ResourceName is required in object payload, skipping")
 	}
 
-	if opts.DryRun {
-		requestJson, err := json.MarshalIndent(payload, "", "    ")
-		if err != nil {
-			cmd.PrintErrln(err)
-			os.Exit(-1)
-		}
-		cmd.Println(string(requestJson))
-		os.Exit(-1)
-	} else if opts.Preview {
+	if opts.DryRun || opts.Preview {
 		requestJson, err := json.MarshalIndent(payload, "", "    ")
 		if err != nil {
-			cmd.PrintErrln(err)
-			os.Exit(-1)
+			return err
 		}
-		cmd.Println(string(requestJson))
+		printer.Println(string(requestJson))
+	}
+	if opts.DryRun {
+		return errDryRun
 	}
 	return nil
 }
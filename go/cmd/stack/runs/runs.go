@@ -0,0 +1,26 @@
+package runs
+
+import (
+	"fmt"
+
+	"github.com/StackGuardian/sg-cli/cmd/stack/runs/watch"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+)
+
+func NewRunsCmd(c *client.Client) *cobra.Command {
+	// runsCmd represents the runs command
+	var runsCmd = &cobra.Command{
+		Use:   "runs",
+		Short: "Inspect the runs of a stack",
+		Long:  `Inspect the runs of a stack and the workflow runs they started.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Println(`Sub-commands:
+  watch       Watch a stack run until it finishes`)
+		},
+	}
+
+	runsCmd.AddCommand(watch.NewWatchCmd(c))
+
+	return runsCmd
+}
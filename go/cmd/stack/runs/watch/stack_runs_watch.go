@@ -0,0 +1,340 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/StackGuardian/sg-cli/dashboard"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-cli/runs"
+	"github.com/StackGuardian/sg-cli/transport"
+	sggosdk "github.com/StackGuardian/sg-sdk-go"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/StackGuardian/sg-sdk-go/core"
+	"github.com/spf13/cobra"
+)
+
+// Options are the flags shared by stack runs watch and the --wait flag of stack apply and destroy
+type Options struct {
+	Interval time.Duration
+}
+
+type RunOptions struct {
+	Options
+	Org    string
+	WfgGrp string
+	Stack  string
+}
+
+func NewWatchCmd(c *client.Client) *cobra.Command {
+	opts := &RunOptions{}
+	// watchCmd represents the watch command
+	var watchCmd = &cobra.Command{
+		Use:   "watch <stack-run-id>",
+		Short: "Watch a stack run until it finishes",
+		Long: `Watch a stack run until it finishes. Whenever one of its workflow runs changes, the workflow runs are printed
+as a tree in dependency order with their status and duration.
+The exit code is 11 if any workflow run errored or failed, with the link to each failed run,
+12 if the stack run was cancelled and 0 once it completed. The global --timeout stops watching with 124.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
+			opts.Org = cmd.Flags().Lookup("org").Value.String()
+			opts.WfgGrp = cmd.Flags().Lookup("workflow-group").Value.String()
+			return ForStackRun(cmd.Context(), c, printer, opts.Org, opts.WfgGrp, opts.Stack, args[0], &opts.Options)
+		},
+	}
+
+	watchCmd.Flags().StringVar(&opts.Stack, "stack-id", "", "The stack ID of the run.")
+	watchCmd.MarkFlagRequired("stack-id")
+	AddFlags(watchCmd, &opts.Options)
+
+	return watchCmd
+}
+
+// AddFlags adds --poll-interval to cmd
+func AddFlags(cmd *cobra.Command, opts *Options) {
+	cmd.Flags().DurationVar(&opts.Interval, "poll-interval", runs.DefaultInterval, "Time between two status checks of the stack run.")
+}
+
+// StackRunID returns the ID of a created stack run
+func StackRunID(response *sggosdk.GeneratedStackRunsResponse) (string, error) {
+	if id := response.GetData().GetStackRunId(); id != "" {
+		return path.Base(id), nil
+	}
+	return "", clierrors.New(clierrors.General, "the API did not return the ID of the stack run, cannot wait for it")
+}
+
+// ForStackRun watches a stack run until it finishes and returns the error for its outcome.
+// The tree of its workflow runs is printed whenever it changes. With --output, the final state of the
+// workflow runs is printed as well.
+func ForStackRun(ctx context.Context, c *client.Client, printer *output.Printer, org, wfGrp, stack, stackRun string, opts *Options) error {
+	if opts.Interval <= 0 {
+		opts.Interval = runs.DefaultInterval
+	}
+	// The API returns the ID of a created stack run as /stackruns/<id>
+	stackRun = path.Base(stackRun)
+	name := "Stack run " + stackRun
+	var last *sggosdk.GeneratedStackRunsGetResponse
+	var wfRuns []map[string]interface{}
+	status, printed := "", ""
+	for {
+		response, err := c.StackRuns.ReadStackRun(ctx, org, stack, stackRun, wfGrp)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return clierrors.Wrap(err, "Failed to read stack run %s", stackRun)
+		}
+		last = response
+		if wfRuns, err = workflowRuns(response); err != nil {
+			return clierrors.Wrap(err, "Failed to decode stack run %s", stackRun)
+		}
+		status = response.Msg.GetLatestStatus()
+		if tree := Tree(wfRuns); status+"\n"+tree != printed {
+			printer.Println(">> [" + time.Now().Format("2006-01-02 15:04:05") + "] " + name + ": " + status)
+			if tree != "" {
+				printer.Println(tree)
+			}
+			printed = status + "\n" + tree
+		}
+		if finished(status, wfRuns) {
+			break
+		}
+		if err := runs.Sleep(ctx, opts.Interval); err != nil {
+			break
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		printer.Println(">> " + name + " is still " + status + ": " + dashboard.Default().StackRun(org, wfGrp, stack, stackRun))
+		return clierrors.Wrap(err, "Stopped waiting for stack run %s", stackRun)
+	}
+
+	if printer.Structured() {
+		if err := printer.Print(last, output.StackRunStatus); err != nil {
+			return err
+		}
+	}
+	printer.Println("To view the Stack run, please visit the following URL:")
+	printer.Println(dashboard.Default().StackRun(org, wfGrp, stack, stackRun))
+	failed := []string{}
+	for _, run := range wfRuns {
+		if runStatus := output.Cell(run["LatestStatus"]); runStatus == runs.Errored || runStatus == runs.Failed {
+			wf, wfRun := runs.WorkflowOf(run), output.Cell(run["ResourceName"])
+			printer.Println(">> Workflow " + wf + " " + runStatus + ": " + dashboard.Default().StackWorkflowRun(org, wfGrp, stack, wf, wfRun))
+			failed = append(failed, wf)
+		}
+	}
+	if len(failed) > 0 {
+		return clierrors.New(clierrors.RunFailed, "%s finished with status %s, failed workflows: %s", name, status, strings.Join(failed, ", "))
+	}
+	return runs.Outcome(name, status, nil)
+}
+
+// workflowRuns decodes the workflow runs of a stack run, keeping numbers as json.Number for the output formats
+func workflowRuns(response *sggosdk.GeneratedStackRunsGetResponse) ([]map[string]interface{}, error) {
+	var msg struct {
+		WfRuns []map[string]interface{}
+	}
+	if response.Msg == nil {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(strings.NewReader(response.Msg.String()))
+	decoder.UseNumber()
+	if err := decoder.Decode(&msg); err != nil {
+		return nil, err
+	}
+	return msg.WfRuns, nil
+}
+
+// finished reports whether a stack run will not change anymore. Without a stack run status,
+// the stack run is finished once all of its workflow runs are.
+func finished(status string, wfRuns []map[string]interface{}) bool {
+	if status != "" {
+		return runs.Terminal(status)
+	}
+	for _, run := range wfRuns {
+		if !runs.Terminal(output.Cell(run["LatestStatus"])) {
+			return false
+		}
+	}
+	return len(wfRuns) > 0
+}
+
+// node is a workflow run in the tree of a stack run
+type node struct {
+	workflow string
+	run      map[string]interface{}
+	parent   *node
+	children []*node
+}
+
+// Tree renders the workflow runs of a stack run in dependency order with their status and duration.
+// A workflow run is printed below the last workflow it depends on, workflow runs without dependencies
+// are printed at the top level in the order of the API.
+func Tree(wfRuns []map[string]interface{}) string {
+	nodes := make([]*node, 0, len(wfRuns))
+	byWorkflow := map[string]*node{}
+	for _, run := range wfRuns {
+		n := &node{workflow: runs.WorkflowOf(run), run: run}
+		if n.workflow == "" {
+			n.workflow = output.Cell(run["ResourceName"])
+		}
+		nodes = append(nodes, n)
+		byWorkflow[n.workflow] = n
+	}
+	roots := []*node{}
+	for _, n := range nodes {
+		for _, dependency := range dependencies(n.run) {
+			if parent, ok := byWorkflow[dependency]; ok && !n.ancestorOf(parent) {
+				n.parent = parent
+			}
+		}
+		if n.parent == nil {
+			roots = append(roots, n)
+		} else {
+			n.parent.children = append(n.parent.children, n)
+		}
+	}
+
+	b := &bytes.Buffer{}
+	w := tabwriter.NewWriter(b, 0, 8, 3, ' ', 0)
+	for _, root := range roots {
+		root.render(w, "", "")
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// ancestorOf reports whether n is other or one of its ancestors, which would make a cycle
+func (n *node) ancestorOf(other *node) bool {
+	for ; other != nil; other = other.parent {
+		if other == n {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *node) render(w io.Writer, branch, indent string) {
+	fmt.Fprintf(w, "%s%s\t%s\t%s\t%s\n", branch, n.workflow, output.Cell(n.run["ResourceName"]),
+		output.Cell(n.run["LatestStatus"]), output.RunDuration(n.run))
+	for i, child := range n.children {
+		if i == len(n.children)-1 {
+			child.render(w, indent+"└─ ", indent+"   ")
+		} else {
+			child.render(w, indent+"├─ ", indent+"│  ")
+		}
+	}
+}
+
+// dependencies returns the workflows a workflow run waits for, as given by the stack
+func dependencies(run map[string]interface{}) []string {
+	var values []interface{}
+	for _, key := range []string{"DependsOn", "dependsOn"} {
+		if list, ok := run[key].([]interface{}); ok {
+			values = list
+		}
+	}
+	if parameters, ok := run["RuntimeParameters"].(map[string]interface{}); ok && values == nil {
+		values, _ = parameters["dependsOn"].([]interface{})
+	}
+	workflows := make([]string, 0, len(values))
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			workflows = append(workflows, v)
+		case map[string]interface{}:
+			if workflow := runs.WorkflowOf(v); workflow != "" {
+				workflows = append(workflows, workflow)
+			} else {
+				workflows = append(workflows, output.Cell(v["id"]))
+			}
+		}
+	}
+	return workflows
+}
+
+// ActiveRuns lists the runs in progress of a stack, for runs.GuardOptions. Only the latest page of stack runs
+// is read, a run in progress is always among the latest runs.
+func ActiveRuns(c *client.Client, org, wfGrp, stack string) runs.ListActive {
+	return func(ctx context.Context) ([]runs.ActiveRun, error) {
+		page, err := c.StackRuns.ListAllStackRuns(ctx, org, stack, wfGrp, &sggosdk.ListAllStackRunsRequest{})
+		if err != nil {
+			if strings.Contains(err.Error(), "the server responded with nothing") {
+				return nil, nil
+			}
+			return nil, clierrors.Wrap(err, "Failed to list the runs of stack %s", stack)
+		}
+		active := []runs.ActiveRun{}
+		for _, run := range page.Msg {
+			id := path.Base(run.StackRunId)
+			if run.StackRunId == "" {
+				id = run.ResourceName
+			}
+			if runs.InProgress(run.LatestStatus) {
+				active = append(active, runs.ActiveRun{ID: id, Name: "Stack run " + id, Status: run.LatestStatus})
+			}
+		}
+		return active, nil
+	}
+}
+
+// CancelRun cancels the workflow runs in progress of a stack run, for runs.GuardOptions
+func CancelRun(c *client.Client, org, wfGrp, stack string) runs.Cancel {
+	return func(ctx context.Context, run runs.ActiveRun) error {
+		response, err := c.StackRuns.ReadStackRun(ctx, org, stack, run.ID, wfGrp)
+		if err != nil {
+			return clierrors.Wrap(err, "Failed to read stack run %s", run.ID)
+		}
+		wfRuns, err := workflowRuns(response)
+		if err != nil {
+			return clierrors.Wrap(err, "Failed to decode stack run %s", run.ID)
+		}
+		for _, wfRun := range wfRuns {
+			if runs.InProgress(output.Cell(wfRun["LatestStatus"])) {
+				if err := cancelWorkflowRun(ctx, org, wfGrp, stack, runs.WorkflowOf(wfRun), output.Cell(wfRun["ResourceName"])); err != nil {
+					return clierrors.Wrap(err, "Failed to cancel stack run %s", run.ID)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// cancelWorkflowRun cancels a workflow run of a stack. The SDK only cancels the runs of workflows outside of stacks.
+func cancelWorkflowRun(ctx context.Context, org, wfGrp, stack, wf, wfRun string) error {
+	settings := config.Current()
+	endpoint := settings.APIURL + "/api/v1/orgs/" + url.PathEscape(org) + "/wfgrps/" + url.PathEscape(wfGrp) +
+		"/stacks/" + url.PathEscape(stack) + "/wfs/" + url.PathEscape(wf) + "/wfruns/" + url.PathEscape(wfRun) + "/cancel/"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "apikey "+settings.APIToken)
+	resp, err := transport.Client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(resp.Body)
+		return core.NewAPIError(resp.StatusCode, errors.New(string(body)))
+	}
+	return nil
+}
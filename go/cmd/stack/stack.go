@@ -3,11 +3,13 @@ package stack
 import (
 	"fmt"
 
+	"github.com/StackGuardian/sg-cli/cmd/cmdutil"
 	"github.com/StackGuardian/sg-cli/cmd/stack/apply"
 	"github.com/StackGuardian/sg-cli/cmd/stack/create"
 	"github.com/StackGuardian/sg-cli/cmd/stack/delete"
 	"github.com/StackGuardian/sg-cli/cmd/stack/destroy"
 	"github.com/StackGuardian/sg-cli/cmd/stack/outputs"
+	"github.com/StackGuardian/sg-cli/cmd/stack/runs"
 	"github.com/StackGuardian/sg-sdk-go/client"
 	"github.com/spf13/cobra"
 )
@@ -15,30 +17,31 @@ import (
 func NewStackCmd(c *client.Client) *cobra.Command {
 	// stackCmd represents the Stack command
 	var stackCmd = &cobra.Command{
-		Use:   "stack",
-		Short: "Manage stacks",
-		Long:  `Manage stacks in Stackguardian platform.`,
+		Use:               "stack",
+		Short:             "Manage stacks",
+		Long:              `Manage stacks in Stackguardian platform.`,
+		PersistentPreRunE: cmdutil.ResolveContext,
 		Run: func(cmd *cobra.Command, args []string) {
 			fmt.Println(`Sub-commands:
   create      Create new stack
   delete      Deletes an existing stack
   apply       Execute "Apply" on existing stack
   destroy     Execute "Destroy" on existing stack
-  outputs     Get outputs from stack`)
+  outputs     Get outputs from stack
+  runs        Inspect the runs of a stack`)
 		},
 	}
 
-	stackCmd.PersistentFlags().String("org", "", "The organization name on Stackguardian platform.")
-	stackCmd.MarkPersistentFlagRequired("org")
+	stackCmd.PersistentFlags().String("org", "", "The organization name on Stackguardian platform. Defaults to the current context.")
 
-	stackCmd.PersistentFlags().String("workflow-group", "", "The workflow group under the organization.")
-	stackCmd.MarkPersistentFlagRequired("workflow-group")
+	stackCmd.PersistentFlags().String("workflow-group", "", "The workflow group under the organization. Defaults to the current context.")
 
 	stackCmd.AddCommand(outputs.NewOutputsCmd(c))
 	stackCmd.AddCommand(destroy.NewDestroyCmd(c))
 	stackCmd.AddCommand(create.NewCreateCmd(c))
 	stackCmd.AddCommand(delete.NewDeleteCmd(c))
 	stackCmd.AddCommand(apply.NewApplyCmd(c))
+	stackCmd.AddCommand(runs.NewRunsCmd(c))
 
 	return stackCmd
 }
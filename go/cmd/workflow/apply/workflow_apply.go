@@ -1,69 +1,102 @@
 package apply
 
 import (
-	"context"
-	"os"
-
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/overrides"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/runs/logs"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/wait"
+	"github.com/StackGuardian/sg-cli/dashboard"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-cli/runs"
 	sggosdk "github.com/StackGuardian/sg-sdk-go"
 	"github.com/StackGuardian/sg-sdk-go/client"
 	"github.com/spf13/cobra"
 )
 
 type RunOptions struct {
-	OutputJson bool
-	Org        string
-	WfgGrp     string
-	WfId       string
+	Org    string
+	WfgGrp string
+	WfId   string
+	Wait   bool
+	Logs   bool
+	wait.Options
+	Overrides overrides.Options
+	Guard     runs.GuardOptions
 }
 
 func NewApplyCmd(c *client.Client) *cobra.Command {
 	opts := &RunOptions{}
-	DASHBOARD_URL := "https://app.stackguardian.io/orchestrator"
 	// applyCmd represents the apply command
 	var applyCmd = &cobra.Command{
 		Use:   "apply",
 		Short: "Execute \"Apply\" on existing workflow",
 		Long:  `Execute "Apply" on existing workflow`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
 			opts.Org = cmd.Parent().PersistentFlags().Lookup("org").Value.String()
 			opts.WfgGrp = cmd.Parent().PersistentFlags().Lookup("workflow-group").Value.String()
 			opts.WfId = cmd.Flags().Lookup("workflow-id").Value.String()
+			request, err := overrides.Request(cmd.Context(), c, opts.Org, opts.WfgGrp, opts.WfId, sggosdk.ActionEnumApply, &opts.Overrides)
+			if err != nil {
+				return err
+			}
+			if opts.Overrides.DryRun {
+				return overrides.Print(printer, request)
+			}
+			if err := overrides.Confirm(cmd, opts.WfId, sggosdk.ActionEnumApply, &opts.Overrides.Targeting); err != nil {
+				return err
+			}
+			if err := opts.Guard.Guard(cmd.Context(), "Workflow "+opts.WfId, runs.WorkflowActiveRuns(c, opts.Org, opts.WfgGrp, opts.WfId),
+				runs.CancelWorkflowRun(c, opts.Org, opts.WfgGrp, opts.WfId), opts.Interval, printer.Println); err != nil {
+				return err
+			}
 			response, err := c.WorkflowRuns.CreateWorkflowRun(
-				context.Background(),
+				cmd.Context(),
 				opts.Org,
 				opts.WfId,
 				opts.WfgGrp,
-				&sggosdk.WorkflowRun{
-					TerraformAction: &sggosdk.TerraformAction{
-						Action: sggosdk.ActionEnumApply.Ptr(),
-					},
-				},
+				request,
 			)
 			if err != nil {
-				cmd.Println(err)
-				os.Exit(-1)
+				return clierrors.Wrap(err, "Failed to apply workflow %s", opts.WfId)
 			}
-			if opts.OutputJson {
-				cmd.Println(response)
+			if printer.Structured() && !opts.Wait && !opts.Logs {
+				if err := printer.Print(response, output.WorkflowRun); err != nil {
+					return err
+				}
 			}
-			cmd.Println("Workflow apply run successfully.")
-			workflowRunPath := DASHBOARD_URL +
-				"/orgs/" +
-				opts.Org +
-				"/wfgrps/" +
-				opts.WfgGrp +
-				"/wfs/" +
-				opts.WfId +
-				"?tab=runs"
-			cmd.Println("To view the workflow run, please visit the following URL:")
-			cmd.Println(workflowRunPath)
+			printer.Println("Workflow apply run successfully.")
+			workflowRunPath := dashboard.Default().WorkflowRuns(opts.Org, opts.WfgGrp, opts.WfId)
+			printer.Println("To view the workflow run, please visit the following URL:")
+			printer.Println(workflowRunPath)
+			if opts.Wait || opts.Logs {
+				run, err := wait.RunID(response)
+				if err != nil {
+					return err
+				}
+				if opts.Logs {
+					if _, err := logs.Stream(cmd.Context(), c, printer.Text(), opts.Org, opts.WfgGrp, opts.WfId, run,
+						&logs.Options{Follow: true, Interval: opts.Interval}); err != nil {
+						return err
+					}
+				}
+				return wait.ForRun(cmd.Context(), c, printer, opts.Org, opts.WfgGrp, opts.WfId, run, &opts.Options)
+			}
+			return nil
 		},
 	}
 
 	applyCmd.Flags().String("workflow-id", "", "The workflow ID to retrieve.")
 	applyCmd.MarkFlagRequired("workflow-id")
-
-	applyCmd.Flags().BoolVar(&opts.OutputJson, "output-json", false, "Output execution response as json to STDIN.")
+	applyCmd.Flags().BoolVar(&opts.Wait, "wait", false, "Wait for the workflow run to finish. The exit code reflects its final status.")
+	applyCmd.Flags().BoolVar(&opts.Logs, "logs", false, "Print the logs of the workflow run until it finishes. Implies --wait.")
+	wait.AddFlags(applyCmd, &opts.Options)
+	overrides.AddFlags(applyCmd, &opts.Overrides)
+	applyCmd.Flags().BoolVarP(&opts.Overrides.Yes, "yes", "y", false, "Do not ask for a confirmation of a targeted run.")
+	runs.AddGuardFlags(applyCmd, &opts.Guard)
 
 	return applyCmd
 }
@@ -0,0 +1,251 @@
+package clone
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/export"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/tfstate"
+	"github.com/StackGuardian/sg-cli/dashboard"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-cli/utilities"
+	sggosdk "github.com/StackGuardian/sg-sdk-go"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+)
+
+// Kinds of the resources a workflow references
+const (
+	Integration = "integration"
+	Secret      = "secret"
+	RunnerGroup = "runner group"
+)
+
+// Reference is a resource of the org that a workflow uses, Path is the JSON path of the field holding it
+type Reference struct {
+	Kind string
+	Name string
+	Path string
+}
+
+type RunOptions struct {
+	Org          string
+	WfgGrp       string
+	WfId         string
+	ToOrg        string
+	ToWfgGrp     string
+	Name         string
+	PatchPayload string
+	CopyState    bool
+}
+
+func NewCloneCmd(c *client.Client) *cobra.Command {
+	opts := &RunOptions{}
+	// cloneCmd represents the clone command
+	var cloneCmd = &cobra.Command{
+		Use:   "clone",
+		Short: "Copy a workflow to another workflow group or org",
+		Long: `Copy a workflow to another workflow group or org, or under a new name.
+The integrations, VCS auth secrets and runner groups the workflow uses are checked in the target org first,
+the workflow is only created when they all exist. --copy-state also copies the Terraform state of the workflow.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
+			opts.Org = cmd.Parent().PersistentFlags().Lookup("org").Value.String()
+			opts.WfgGrp = cmd.Parent().PersistentFlags().Lookup("workflow-group").Value.String()
+			if opts.ToOrg == "" {
+				opts.ToOrg = opts.Org
+			}
+			if opts.ToWfgGrp == "" {
+				opts.ToWfgGrp = opts.WfgGrp
+			}
+			if opts.Name == "" {
+				opts.Name = opts.WfId
+			}
+			if opts.ToOrg == opts.Org && opts.ToWfgGrp == opts.WfgGrp && opts.Name == opts.WfId {
+				return clierrors.New(clierrors.Usage, "the clone needs a new --name, or another --to-workflow-group or --to-org")
+			}
+			if opts.PatchPayload != "" && !utilities.IsObject(opts.PatchPayload) {
+				return clierrors.New(clierrors.Validation, "--patch-payload requires a JSON object")
+			}
+
+			payload, err := export.Read(cmd.Context(), c, opts.Org, opts.WfgGrp, opts.WfId)
+			if err != nil {
+				return err
+			}
+			payload["ResourceName"] = opts.Name
+			data, err := json.Marshal(payload)
+			if err != nil {
+				return err
+			}
+			if opts.PatchPayload != "" {
+				data = []byte(utilities.PatchJSON(string(data), opts.PatchPayload))
+				if err := json.Unmarshal(data, &payload); err != nil {
+					return &clierrors.Error{Category: clierrors.Validation, Message: "Error during patching Workflow payload", Err: err}
+				}
+			}
+			var request *sggosdk.Workflow
+			if err := json.Unmarshal(data, &request); err != nil {
+				return &clierrors.Error{Category: clierrors.Validation, Message: "Error while unmarshalling Workflow payload", Err: err}
+			}
+
+			missing, err := Missing(cmd.Context(), c, opts.ToOrg, References(payload))
+			if err != nil {
+				return err
+			}
+			if len(missing) > 0 {
+				for _, reference := range missing {
+					cmd.PrintErrf(">> [ERROR] %s %s used by %s does not exist in org %s\n", reference.Kind, reference.Name, reference.Path, opts.ToOrg)
+				}
+				return clierrors.New(clierrors.Validation, "%d resources used by workflow %s are missing in org %s", len(missing), opts.WfId, opts.ToOrg)
+			}
+
+			response, err := c.Workflows.CreateWorkflow(cmd.Context(), opts.ToOrg, opts.ToWfgGrp, request)
+			if err != nil {
+				return clierrors.Wrap(err, "Failed to create workflow %s", opts.Name)
+			}
+			if printer.Structured() {
+				if err := printer.Print(response, output.Created); err != nil {
+					return err
+				}
+			}
+			printer.Println("Workflow " + opts.WfId + " cloned to " + opts.Name + " successfully.")
+			if opts.CopyState {
+				if err := copyState(cmd.Context(), c, printer, opts); err != nil {
+					return clierrors.Wrap(err, "Failed to copy the state of workflow %s to %s", opts.WfId, opts.Name)
+				}
+			}
+			printer.Println("To view the workflow, please visit the following URL:")
+			printer.Println(dashboard.Default().Workflow(opts.ToOrg, opts.ToWfgGrp, opts.Name))
+			return nil
+		},
+	}
+
+	cloneCmd.Flags().StringVar(&opts.WfId, "workflow-id", "", "The workflow ID to clone.")
+	cloneCmd.MarkFlagRequired("workflow-id")
+	cloneCmd.Flags().StringVar(&opts.ToOrg, "to-org", "", "The org of the clone. Defaults to --org.")
+	cloneCmd.Flags().StringVar(&opts.ToWfgGrp, "to-workflow-group", "", "The workflow group of the clone. Defaults to --workflow-group.")
+	cloneCmd.Flags().StringVar(&opts.Name, "name", "", "The workflow ID of the clone. Defaults to the ID of the cloned workflow.")
+	cloneCmd.Flags().StringVar(&opts.PatchPayload, "patch-payload", "", "Patch the payload of the clone. Add or replace values. Requires valid JSON input.")
+	cloneCmd.Flags().BoolVar(&opts.CopyState, "copy-state", false, "Copy the latest Terraform state of the workflow to the clone.")
+
+	return cloneCmd
+}
+
+// References returns the integrations, secrets and runner groups a workflow payload uses
+func References(payload map[string]interface{}) []Reference {
+	references := []Reference{}
+	platforms, _ := payload["DeploymentPlatformConfig"].([]interface{})
+	for i, platform := range platforms {
+		config, _ := lookup(platform, "config").(map[string]interface{})
+		if id, ok := config["integrationId"].(string); ok && id != "" {
+			references = append(references, reference(id, fmt.Sprintf("$.DeploymentPlatformConfig[%d].config.integrationId", i)))
+		}
+	}
+	if auth, ok := lookup(payload["VCSConfig"], "iacVCSConfig", "customSource", "config", "auth").(string); ok && auth != "" {
+		references = append(references, reference(auth, "$.VCSConfig.iacVCSConfig.customSource.config.auth"))
+	}
+	if lookup(payload["RunnerConstraints"], "type") == string(sggosdk.RunnerConstraintsTypeEnumPrivate) {
+		names, _ := lookup(payload["RunnerConstraints"], "names").([]interface{})
+		for i, name := range names {
+			if name, ok := name.(string); ok && name != "" {
+				references = append(references, Reference{Kind: RunnerGroup, Name: name, Path: fmt.Sprintf("$.RunnerConstraints.names[%d]", i)})
+			}
+		}
+	}
+	return references
+}
+
+// reference returns the reference of an ID such as /integrations/aws-prod or /secrets/github-token
+func reference(id, path string) Reference {
+	if name, ok := strings.CutPrefix(id, "/secrets/"); ok {
+		return Reference{Kind: Secret, Name: name, Path: path}
+	}
+	return Reference{Kind: Integration, Name: strings.TrimPrefix(id, "/integrations/"), Path: path}
+}
+
+// Missing returns the references that do not exist in org
+func Missing(ctx context.Context, c *client.Client, org string, references []Reference) ([]Reference, error) {
+	missing := []Reference{}
+	var secrets map[string]bool
+	for _, ref := range references {
+		var err error
+		switch ref.Kind {
+		case Integration:
+			_, err = c.Connectors.ReadConnector(ctx, ref.Name, org)
+		case RunnerGroup:
+			_, err = c.RunnerGroups.ReadRunnerGroup(ctx, org, ref.Name, &sggosdk.ReadRunnerGroupRequest{})
+		case Secret:
+			if secrets == nil {
+				list, err := c.Secrets.ListAllSecrets(ctx, org)
+				if err != nil && clierrors.From(err).Category != clierrors.NotFound {
+					return nil, clierrors.Wrap(err, "Failed to list the secrets of org %s", org)
+				}
+				secrets = map[string]bool{}
+				for _, secret := range list.GetMsg() {
+					secrets[secret.ResourceName] = true
+				}
+			}
+			if !secrets[ref.Name] {
+				missing = append(missing, ref)
+			}
+			continue
+		}
+		if err != nil {
+			if clierrors.From(err).Category != clierrors.NotFound {
+				return nil, clierrors.Wrap(err, "Failed to read %s %s in org %s", ref.Kind, ref.Name, org)
+			}
+			missing = append(missing, ref)
+		}
+	}
+	return missing, nil
+}
+
+// copyState copies the Terraform state of the cloned workflow to the clone through a temporary file
+func copyState(ctx context.Context, c *client.Client, printer *output.Printer, opts *RunOptions) error {
+	file, err := os.CreateTemp("", "sg-cli-*.tfstate")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	downloaded, err := tfstate.Download(ctx, c, opts.Org, opts.WfgGrp, opts.WfId, file)
+	if err != nil {
+		return err
+	}
+	if !downloaded {
+		printer.Println(">> Workflow " + opts.WfId + " has no state, skipping the state copy.")
+		return nil
+	}
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := tfstate.Upload(ctx, opts.ToOrg, opts.ToWfgGrp, opts.Name, file, size); err != nil {
+		return err
+	}
+	printer.Println(">> State file copied successfully.")
+	return nil
+}
+
+// lookup returns the value at the keys of nested objects, nil if one is missing
+func lookup(value interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
@@ -1,21 +1,33 @@
 package create
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
-	"io"
-	"net/http"
+	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/tfstate"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/wait"
+	"github.com/StackGuardian/sg-cli/dashboard"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-cli/transport"
 	"github.com/StackGuardian/sg-cli/utilities"
 	sggosdk "github.com/StackGuardian/sg-sdk-go"
 	"github.com/StackGuardian/sg-sdk-go/client"
 	"github.com/spf13/cobra"
 )
 
+// BulkSummary is added to the error of a bulk import that did not fully succeed
+type BulkSummary struct {
+	Created int      `json:"created"`
+	Updated int      `json:"updated"`
+	Failed  []string `json:"failed"`
+	Skipped int      `json:"skipped"`
+	Retries int64    `json:"retries"`
+}
+
 // Extend the Workflow struct from the sdk to add the new field for bulk
 type BulkWorkflow struct {
 	sggosdk.Workflow
@@ -43,13 +55,10 @@ type RunOptions struct {
 	Preview      bool
 	DryRun       bool
 	Run          bool
-	OutputJson   bool
+	Wait         bool
 	PatchPayload string
 	Payload      string
-}
-
-type tfStateUploadUrlResponse struct {
-	Msg string `json:"msg"`
+	wait.Options
 }
 
 func (o *BulkWorkflow) UnmarshalJSON(data []byte) error {
@@ -70,38 +79,50 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 		Short: "Create new workflow",
 		Long:  `Create new workflow in the specified organization and workflow group.`,
 		Args:  cobra.ExactArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			// Set the options from the command line flags
 			opts.Org = cmd.Parent().PersistentFlags().Lookup("org").Value.String()
 			opts.WfgGrp = cmd.Parent().PersistentFlags().Lookup("workflow-group").Value.String()
 			opts.Payload = args[0]
-
-			DASHBOARD_URL := "https://app.stackguardian.io/orchestrator"
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
+			if opts.Wait && (!opts.Run || opts.Bulk) {
+				return clierrors.New(clierrors.Usage, "--wait requires --run and cannot be used with --bulk")
+			}
 
 			payload, err := os.ReadFile(opts.Payload)
 			if err != nil {
-				cmd.PrintErrln(err)
+				return clierrors.Wrap(err, "Failed to read workflow payload")
 			}
 			if opts.Bulk {
 				// Unmarshal the array payload into a slice of BulkWorkflow objects
 				var createBulkWorkflowRequest []BulkWorkflow
 				err := json.Unmarshal(payload, &createBulkWorkflowRequest)
 				if err != nil {
-					cmd.Println("Please provide a valid JSON payload. Bulk Payload should be an array of objects.")
-					cmd.PrintErrln(err)
-					os.Exit(-1)
+					return &clierrors.Error{Category: clierrors.Validation, Message: "Please provide a valid JSON payload. Bulk Payload should be an array of objects", Err: err}
 				}
 
 				// tempMap is needed to delete the CLIConfiguration field and create the workflow request
 				var tempMap []map[string]interface{}
 				err = json.Unmarshal(payload, &tempMap)
 				if err != nil {
-					cmd.PrintErrln(err)
-					os.Exit(-1)
+					return err
 				}
 
+				created, updated, processed, retriesBefore := 0, 0, 0, transport.Retries()
+				lastWorkflow := ""
+				var failedWorkflows []string
+				succeeded := map[string]bool{}
 				// Iterate over the slice of BulkWorkflow objects
 				for idx, bulkWorkflow := range createBulkWorkflowRequest {
+					// Stop taking new workflows once the command is interrupted or timed out
+					if cmd.Context().Err() != nil {
+						break
+					}
+					processed++
+					lastWorkflow = bulkWorkflow.ResourceName.Value
 					var individualWorkflow *sggosdk.Workflow
 					// delete the CLIConfiguration field from the tempMap
 					delete(tempMap[idx], "CLIConfiguration")
@@ -116,10 +137,13 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 						cmd.PrintErrln(err)
 						continue
 					}
-					cmd.Println(">> Processing workflow: " + individualWorkflow.ResourceName.Value)
-					err = performPreExecutionFlagChecks(cmd, individualWorkflow, opts)
+					printer.Println(">> Processing workflow: " + individualWorkflow.ResourceName.Value)
+					err = performPreExecutionFlagChecks(printer, individualWorkflow, opts)
+					if errors.Is(err, errDryRun) {
+						continue
+					}
 					if err != nil {
-						cmd.PrintErrln(err)
+						cmd.PrintErrln(">> [ERROR] " + err.Error() + ", skipping")
 						continue
 					}
 					// If the workflow group is provided in the bulk payload, use it. Otherwise, use the one provided in the command
@@ -129,7 +153,7 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 						opts.WfgGrp = cmd.Parent().PersistentFlags().Lookup("workflow-group").Value.String()
 					}
 					response, err := c.Workflows.CreateWorkflow(
-						context.Background(),
+						cmd.Context(),
 						opts.Org,
 						opts.WfgGrp,
 						individualWorkflow,
@@ -140,37 +164,42 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 							cmd.PrintErrln(err)
 							continue
 						} else {
-							cmd.Println("Workflow already exists, updating instead...")
+							printer.Println("Workflow already exists, updating instead...")
 							// convert to update workflow request
 							var updateIndividualWorkflow *sggosdk.PatchedWorkflow
-							err = json.Unmarshal(jsonBody, &individualWorkflow)
+							err = json.Unmarshal(jsonBody, &updateIndividualWorkflow)
 							if err != nil {
 								cmd.PrintErrln(err)
 								continue
 							}
 							response, err := c.Workflows.UpdateWorkflow(
-								context.Background(),
+								cmd.Context(),
 								opts.Org,
 								individualWorkflow.ResourceName.Value,
 								opts.WfgGrp,
 								updateIndividualWorkflow,
 							)
 							if err != nil {
-								cmd.PrintErrln(">> [ERROR] Updating workflow failed for resource name: " + *updateIndividualWorkflow.ResourceName + "\n")
+								cmd.PrintErrln(">> [ERROR] Updating workflow failed for resource name: " + individualWorkflow.ResourceName.Value + "\n")
 								cmd.PrintErrln(err)
 								continue
 							}
-							if opts.OutputJson {
-								cmd.Println(response)
+							if printer.Structured() {
+								if err := printer.Print(response, output.Created); err != nil {
+									cmd.PrintErrln(err)
+								}
 							}
-							cmd.Println("Workflow updated successfully.")
+							printer.Println("Workflow updated successfully.")
+							updated++
+							succeeded[bulkWorkflow.ResourceName.Value] = true
 
 							if bulkWorkflow.CLIConfiguration.CLIConfiguration.TfStateFilePath == "" {
-								cmd.Println("TfStateFilePath is not provided for workflow: " + bulkWorkflow.ResourceName.Value)
-								cmd.Println(">> Skipping update of state file..\n")
+								printer.Println("TfStateFilePath is not provided for workflow: " + bulkWorkflow.ResourceName.Value)
+								printer.Println(">> Skipping update of state file..")
+								printer.Println()
 							} else {
-								cmd.Println(">> Attempting to upload state file..")
-								err = uploadTfState(cmd, &bulkWorkflow, opts)
+								printer.Println(">> Attempting to upload state file..")
+								err = uploadTfState(cmd, printer, &bulkWorkflow, opts)
 								if err != nil {
 									cmd.PrintErrln("Failed to upload state file for workflow: " + individualWorkflow.ResourceName.Value + "\n")
 									continue
@@ -178,16 +207,20 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 							}
 						}
 					} else {
-						if opts.OutputJson {
-							cmd.Println(response)
+						if printer.Structured() {
+							if err := printer.Print(response, output.Created); err != nil {
+								cmd.PrintErrln(err)
+							}
 						}
-						cmd.Println("Workflow created successfully.")
+						printer.Println("Workflow created successfully.")
+						created++
+						succeeded[bulkWorkflow.ResourceName.Value] = true
 						if bulkWorkflow.CLIConfiguration.CLIConfiguration.TfStateFilePath == "" {
 							cmd.PrintErrln("[ERROR] TfStateFilePath is not provided for workflow: " + bulkWorkflow.ResourceName.Value)
 							cmd.PrintErrln(">> Skipping update of state file..")
 						} else {
-							cmd.Println(">> Attempting to upload state file..")
-							err = uploadTfState(cmd, &bulkWorkflow, opts)
+							printer.Println(">> Attempting to upload state file..")
+							err = uploadTfState(cmd, printer, &bulkWorkflow, opts)
 							if err != nil {
 								cmd.PrintErrln("Failed to upload state file for workflow: " + individualWorkflow.ResourceName.Value + "\n")
 							}
@@ -201,7 +234,7 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 								continue
 							}
 							response, err := c.WorkflowRuns.CreateWorkflowRun(
-								context.Background(),
+								cmd.Context(),
 								opts.Org,
 								bulkWorkflow.ResourceName.Value,
 								opts.WfgGrp,
@@ -212,25 +245,50 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 								cmd.PrintErrln(err)
 								continue
 							}
-							if opts.OutputJson {
-								cmd.Println(response)
+							if printer.Structured() {
+								if err := printer.Print(response, output.WorkflowRun); err != nil {
+									cmd.PrintErrln(err)
+								}
 							}
-							cmd.Println("Workflow run created successfully.")
-							workflowRunPath := DASHBOARD_URL +
-								"/orgs/" +
-								opts.Org +
-								"/wfgrps/" +
-								opts.WfgGrp +
-								"/wfs/" +
-								bulkWorkflow.ResourceName.Value +
-								"?tab=runs"
-							cmd.Println("To view the workflow run, please visit the following URL:")
-							cmd.Println(workflowRunPath)
+							printer.Println("Workflow run created successfully.")
+							workflowRunPath := dashboard.Default().WorkflowRuns(opts.Org, opts.WfgGrp, bulkWorkflow.ResourceName.Value)
+							printer.Println("To view the workflow run, please visit the following URL:")
+							printer.Println(workflowRunPath)
 							//new line for formatting
-							cmd.Println()
+							printer.Println()
 						}
 					}
 				}
+				if opts.DryRun {
+					return nil
+				}
+				for _, workflow := range createBulkWorkflowRequest[:processed] {
+					if !succeeded[workflow.ResourceName.Value] {
+						failedWorkflows = append(failedWorkflows, workflow.ResourceName.Value)
+					}
+				}
+				cmd.PrintErrf(">> Bulk import finished: %d created, %d updated, %d failed, %d skipped, %d requests retried\n",
+					created, updated, len(failedWorkflows), len(createBulkWorkflowRequest)-processed, transport.Retries()-retriesBefore)
+				summary := &BulkSummary{
+					Created: created, Updated: updated, Failed: failedWorkflows,
+					Skipped: len(createBulkWorkflowRequest) - processed, Retries: transport.Retries() - retriesBefore,
+				}
+				if err := cmd.Context().Err(); err != nil {
+					if lastWorkflow != "" {
+						cmd.PrintErrln(">> The last processed workflow was " + lastWorkflow + ", check its state on the dashboard: " +
+							dashboard.Default().Workflow(opts.Org, opts.WfgGrp, lastWorkflow))
+					}
+					classified := clierrors.Wrap(err, "Bulk import stopped before all workflows were processed")
+					classified.Details = summary
+					return classified
+				}
+				if len(failedWorkflows) > 0 {
+					return &clierrors.Error{
+						Category: clierrors.PartialFailure,
+						Message:  fmt.Sprintf("%d of %d workflows failed: %s", len(failedWorkflows), processed, strings.Join(failedWorkflows, ", ")),
+						Details:  summary,
+					}
+				}
 			} else {
 				var createWorkflowRequest *sggosdk.Workflow
 				var createWorkflowRunRequest *sggosdk.WorkflowRun
@@ -241,8 +299,7 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 						[]byte(patchedJson),
 						&createWorkflowRequest)
 					if err != nil {
-						cmd.Printf("Error during patching Workflowpayload: %s\n", err)
-						os.Exit(-1)
+						return &clierrors.Error{Category: clierrors.Validation, Message: "Error during patching Workflow payload", Err: err}
 					}
 					//unmarshal patched workflow run
 					if opts.Run {
@@ -250,8 +307,7 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 							[]byte(patchedJson),
 							&createWorkflowRunRequest)
 						if err != nil {
-							cmd.Printf("Error during patching WorkflowRun payload: %s\n", err)
-							os.Exit(-1)
+							return &clierrors.Error{Category: clierrors.Validation, Message: "Error during patching WorkflowRun payload", Err: err}
 						}
 					}
 				} else {
@@ -259,8 +315,7 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 						payload,
 						&createWorkflowRequest)
 					if err != nil {
-						cmd.Printf("Error while unmarshalling Workflow payload: %s\n", err)
-						os.Exit(-1)
+						return &clierrors.Error{Category: clierrors.Validation, Message: "Error while unmarshalling Workflow payload", Err: err}
 					}
 
 					// Ummarshal unpatched workflow run
@@ -269,65 +324,66 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 							payload,
 							&createWorkflowRunRequest)
 						if err != nil {
-							cmd.Printf("Error while unmarshalling WorkflowRun payload: %s\n", err)
-							os.Exit(-1)
+							return &clierrors.Error{Category: clierrors.Validation, Message: "Error while unmarshalling WorkflowRun payload", Err: err}
 						}
 					}
 				}
 
 				// Perform actions based on the set flags
-				if err := performPreExecutionFlagChecks(cmd, createWorkflowRequest, opts); err != nil {
-					cmd.PrintErrln(err)
-					os.Exit(-1)
+				if err := performPreExecutionFlagChecks(printer, createWorkflowRequest, opts); err != nil {
+					if errors.Is(err, errDryRun) {
+						return nil
+					}
+					return err
 				}
 
 				// Run on create
 				if opts.Run {
 					response, err := c.WorkflowRuns.CreateWorkflowRun(
-						context.Background(),
+						cmd.Context(),
 						opts.Org,
 						createWorkflowRequest.ResourceName.Value,
 						opts.WfgGrp,
 						createWorkflowRunRequest,
 					)
 					if err != nil {
-						cmd.PrintErrln("== Failed To Create Workflow Run ==")
-						cmd.PrintErrln(err)
-						os.Exit(-1)
+						return clierrors.Wrap(err, "Failed to create workflow run")
+					}
+					if printer.Structured() && !opts.Wait {
+						if err := printer.Print(response, output.WorkflowRun); err != nil {
+							return err
+						}
 					}
-					if opts.OutputJson {
-						cmd.Println(response)
+					printer.Println("Workflow run created successfully.")
+					workflowRunPath := dashboard.Default().WorkflowRuns(opts.Org, opts.WfgGrp, createWorkflowRequest.ResourceName.Value)
+					printer.Println("To view the workflow run, please visit the following URL:")
+					printer.Println(workflowRunPath)
+					if opts.Wait {
+						run, err := wait.RunID(response)
+						if err != nil {
+							return err
+						}
+						return wait.ForRun(cmd.Context(), c, printer, opts.Org, opts.WfgGrp, createWorkflowRequest.ResourceName.Value, run, &opts.Options)
 					}
-					cmd.Println("Workflow run created successfully.")
-					workflowRunPath := DASHBOARD_URL +
-						"/orgs/" +
-						opts.Org +
-						"/wfgrps/" +
-						opts.WfgGrp +
-						"/wfs/" +
-						createWorkflowRequest.ResourceName.Value +
-						"?tab=runs"
-					cmd.Println("To view the workflow run, please visit the following URL:")
-					cmd.Println(workflowRunPath)
 				} else {
 					response, err := c.Workflows.CreateWorkflow(
-						context.Background(),
+						cmd.Context(),
 						opts.Org,
 						opts.WfgGrp,
 						createWorkflowRequest,
 					)
 					if err != nil {
-						cmd.PrintErrln("== Failed To Create Workflow ==")
-						cmd.PrintErrln(err)
-						os.Exit(-1)
+						return clierrors.Wrap(err, "Failed to create workflow")
 					}
-					if opts.OutputJson {
-						cmd.Println(response)
+					if printer.Structured() {
+						if err := printer.Print(response, output.Created); err != nil {
+							return err
+						}
 					}
-					cmd.Println("Workflow created successfully.")
+					printer.Println("Workflow created successfully.")
 				}
 			}
-
+			return nil
 		},
 	}
 
@@ -335,8 +391,6 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 
 	createCmd.Flags().StringVar(&opts.PatchPayload, "patch-payload", "", "Patch original payload.json input. Add or replace values. Requires valid JSON input.")
 
-	createCmd.Flags().BoolVar(&opts.OutputJson, "output-json", false, "Output execution response as json to STDIN.")
-
 	createCmd.Flags().BoolVar(&opts.Preview, "preview", false, "Preview payload content before applying. Execution will not pause.")
 
 	createCmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Similar to --preview. But execution will stop, nothing will be applied.")
@@ -345,123 +399,58 @@ func NewCreateCmd(c *client.Client) *cobra.Command {
 
 	createCmd.Flags().BoolVar(&opts.Run, "run", false, "Executes the workflow. Used together with --bulk.")
 
+	createCmd.Flags().BoolVar(&opts.Wait, "wait", false, "Wait for the workflow run started by --run to finish. The exit code reflects its final status.")
+	wait.AddFlags(createCmd, &opts.Options)
+
 	return createCmd
 }
 
+// errDryRun stops the processing of a workflow after its payload has been printed
+var errDryRun = errors.New("dry run")
+
 // performPreExecutionFlagChecks performs pre-execution flag checks and returns the payload
-func performPreExecutionFlagChecks(cmd *cobra.Command, payload *sggosdk.Workflow, opts *RunOptions) error {
+func performPreExecutionFlagChecks(printer *output.Printer, payload *sggosdk.Workflow, opts *RunOptions) error {
 
 	if payload.ResourceName == nil || payload.ResourceName.Value == "" {
-		return errors.New(">> [ERROR] Workflow ResourceName is required in object payload, skipping")
+		return clierrors.New(clierrors.Validation, "Workflow ResourceName is required in object payload")
 	}
 
-	if opts.DryRun {
+	if opts.DryRun || opts.Preview {
 		requestJson, err := json.MarshalIndent(payload, "", "    ")
 		if err != nil {
-			cmd.PrintErrln(err)
-			os.Exit(-1)
+			return err
 		}
-		cmd.Println(string(requestJson))
-		os.Exit(-1)
-	} else if opts.Preview {
-		requestJson, err := json.MarshalIndent(payload, "", "    ")
-		if err != nil {
-			cmd.PrintErrln(err)
-			os.Exit(-1)
-		}
-		cmd.Println(string(requestJson))
+		printer.Println(string(requestJson))
+	}
+	if opts.DryRun {
+		return errDryRun
 	}
 	return nil
 }
 
-// uploadTfState uploads the Terraform state file to Stackguardian
-func uploadTfState(cmd *cobra.Command, payload *BulkWorkflow, opts *RunOptions) error {
-	SG_BASE_URL := os.Getenv("SG_BASE_URL")
-	SG_API_TOKEN := os.Getenv("SG_API_TOKEN")
-
-	// Get the tfstate upload url for the workflow
-	url := SG_BASE_URL + "/api/v1/orgs/" +
-		opts.Org +
-		"/wfgrps/" +
-		opts.WfgGrp +
-		"/wfs/" +
-		payload.ResourceName.Value +
-		"/tfstate_upload_url"
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		cmd.PrintErrln(">> [ERROR] Failed to get tfstate upload url for workflow: " + payload.ResourceName.Value + "\n")
-		cmd.PrintErrln(err)
-		return err
-	}
-	req.Header.Set("Authorization", "apikey "+SG_API_TOKEN)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// uploadTfState uploads the Terraform state file of a workflow to Stackguardian
+func uploadTfState(cmd *cobra.Command, printer *output.Printer, payload *BulkWorkflow, opts *RunOptions) error {
+	path := payload.CLIConfiguration.CLIConfiguration.TfStateFilePath
+	stateFile, err := os.Open(path)
 	if err != nil {
-		cmd.PrintErrln(">> [ERROR] Failed to get tfstate upload url for workflow: " + payload.ResourceName.Value + "\n")
+		cmd.PrintErrln(">> [ERROR] Failed to access state file : " + path + ". Please check if the state file exists and is accessible.")
 		cmd.PrintErrln(err)
 		return err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		cmd.PrintErrln(">> [ERROR] Failed to get tfstate upload url for workflow: " + payload.ResourceName.Value + "\n")
-		cmd.PrintErrln("Expected status code 200, got " + resp.Status)
-		return err
-	}
-	body, err := io.ReadAll(resp.Body)
+	defer stateFile.Close()
+	info, err := stateFile.Stat()
 	if err != nil {
-		cmd.PrintErrln(">> [ERROR] Failed to get tfstate upload url for workflow: " + payload.ResourceName.Value + "\n")
+		cmd.PrintErrln(">> [ERROR] Failed to access state file : " + path + ". Please check if the state file exists and is accessible.")
 		cmd.PrintErrln(err)
 		return err
 	}
-	var response tfStateUploadUrlResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		cmd.PrintErrln(">> [ERROR] Failed to get tfstate upload url for workflow: " + payload.ResourceName.Value + "\n")
-		cmd.PrintErrln(err)
-		return err
-	}
-	tfUploadUrl := response.Msg
 
-	// Use the tfUploadUrl to upload the state file to Stackguardian
-	cmd.Println(">> Uploading state file to Stackguardian..")
-	// Create a temporary directory to store the state file
-	tmpDir, err := os.MkdirTemp("", opts.Org+"-"+opts.WfgGrp)
-	if err != nil {
-		cmd.PrintErrln(">> [ERROR] Failed to create temp directory for state file upload: " + payload.ResourceName.Value + "\n")
-		cmd.PrintErrln(err)
-		return err
-	}
-	defer os.RemoveAll(tmpDir)
-	copyFileCmd := exec.Command("cp", payload.CLIConfiguration.CLIConfiguration.TfStateFilePath, tmpDir+"/tfstate.json")
-	err = copyFileCmd.Run()
-	if err != nil {
-		cmd.PrintErrln(">> [ERROR] Failed to access state file : " + payload.CLIConfiguration.CLIConfiguration.TfStateFilePath +
-			". Please check if the state file exists and is accessible.")
+	printer.Println(">> Uploading state file to Stackguardian..")
+	if err := tfstate.Upload(cmd.Context(), opts.Org, opts.WfgGrp, payload.ResourceName.Value, stateFile, info.Size()); err != nil {
+		cmd.PrintErrln(">> [ERROR] Failed to upload state file for workflow: " + payload.ResourceName.Value + "\n")
 		cmd.PrintErrln(err)
 		return err
 	}
-
-	// Use curl to upload the state file to the tfUploadUrl
-	// TODO: Implement this using Go's native HTTP client
-	curlCmd := exec.Command("curl", "-i", "-s", "-X", "PUT",
-		"-H", "Accept: application/json, text/plain, */*",
-		"-H", "Content-Type: application/json",
-		"-H", "ContentType: application/json",
-		"-T", tmpDir+"/tfstate.json",
-		tfUploadUrl,
-	)
-
-	output, err := curlCmd.CombinedOutput()
-	if err != nil {
-		cmd.PrintErrln(">> [ERROR] Error running curl command:", err)
-	}
-
-	if strings.Contains(string(output), "HTTP/1.1 200 OK") {
-		cmd.Println(">> State file uploaded successfully.")
-	} else {
-		cmd.PrintErrln(">> [ERROR] Failed to upload state file for workflow: " + payload.ResourceName.Value + "\n")
-		cmd.PrintErrln(string(output))
-	}
-
+	printer.Println(">> State file uploaded successfully.")
 	return nil
 }
@@ -1,18 +1,16 @@
 package delete
 
 import (
-	"context"
-	"os"
-
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/output"
 	"github.com/StackGuardian/sg-sdk-go/client"
 	"github.com/spf13/cobra"
 )
 
 type RunOptions struct {
-	OutputJson bool
-	Org        string
-	WfgGrp     string
-	WfId       string
+	Org    string
+	WfgGrp string
+	WfId   string
 }
 
 func NewDeleteCmd(c *client.Client) *cobra.Command {
@@ -22,32 +20,36 @@ func NewDeleteCmd(c *client.Client) *cobra.Command {
 		Use:   "delete",
 		Short: "Delete the workflow from workflow group",
 		Long:  `Delete the workflow from workflow group`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
 			opts.Org = cmd.Parent().PersistentFlags().Lookup("org").Value.String()
 			opts.WfgGrp = cmd.Parent().PersistentFlags().Lookup("workflow-group").Value.String()
 			opts.WfId = cmd.Flags().Lookup("workflow-id").Value.String()
 
 			response, err := c.Workflows.DeleteWorkflow(
-				context.Background(),
+				cmd.Context(),
 				opts.Org,
 				opts.WfId,
 				opts.WfgGrp,
 			)
 			if err != nil {
-				cmd.Println(err)
-				os.Exit(-1)
+				return clierrors.Wrap(err, "Failed to delete workflow %s", opts.WfId)
 			}
-			if opts.OutputJson {
-				cmd.Println(response)
+			if printer.Structured() {
+				if err := printer.Print(response, output.Message); err != nil {
+					return err
+				}
 			}
-			cmd.Println("Workflow deleted successfully.")
+			printer.Println("Workflow deleted successfully.")
+			return nil
 		},
 	}
 
 	deleteCmd.Flags().String("workflow-id", "", "The workflow ID to delete.")
 	deleteCmd.MarkFlagRequired("workflow-id")
 
-	deleteCmd.Flags().BoolVar(&opts.OutputJson, "output-json", false, "Output execution response as json to STDIN.")
-
 	return deleteCmd
 }
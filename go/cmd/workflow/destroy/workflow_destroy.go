@@ -1,70 +1,92 @@
 package destroy
 
 import (
-	"context"
-	"os"
-
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/overrides"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/wait"
+	"github.com/StackGuardian/sg-cli/dashboard"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-cli/runs"
 	sggosdk "github.com/StackGuardian/sg-sdk-go"
 	"github.com/StackGuardian/sg-sdk-go/client"
 	"github.com/spf13/cobra"
 )
 
 type RunOptions struct {
-	OutputJson bool
-	Org        string
-	WfgGrp     string
-	WfId       string
+	Org    string
+	WfgGrp string
+	WfId   string
+	Wait   bool
+	wait.Options
+	Guard     runs.GuardOptions
+	Targeting overrides.Targeting
 }
 
 func NewDestroyCmd(c *client.Client) *cobra.Command {
 	opts := &RunOptions{}
-	DASHBOARD_URL := "https://app.stackguardian.io/orchestrator"
 
 	// destroyCmd represents the apply command
 	var destroyCmd = &cobra.Command{
 		Use:   "destroy",
 		Short: "Execute \"Destroy\" on existing workflow",
 		Long:  `Execute "Destroy" on existing workflow`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
 			opts.Org = cmd.Parent().PersistentFlags().Lookup("org").Value.String()
 			opts.WfgGrp = cmd.Parent().PersistentFlags().Lookup("workflow-group").Value.String()
 			opts.WfId = cmd.Flags().Lookup("workflow-id").Value.String()
+			request, err := overrides.Request(cmd.Context(), c, opts.Org, opts.WfgGrp, opts.WfId, sggosdk.ActionEnumDestroy,
+				&overrides.Options{Targeting: opts.Targeting})
+			if err != nil {
+				return err
+			}
+			if err := overrides.Confirm(cmd, opts.WfId, sggosdk.ActionEnumDestroy, &opts.Targeting); err != nil {
+				return err
+			}
+			if err := opts.Guard.Guard(cmd.Context(), "Workflow "+opts.WfId, runs.WorkflowActiveRuns(c, opts.Org, opts.WfgGrp, opts.WfId),
+				runs.CancelWorkflowRun(c, opts.Org, opts.WfgGrp, opts.WfId), opts.Interval, printer.Println); err != nil {
+				return err
+			}
 			response, err := c.WorkflowRuns.CreateWorkflowRun(
-				context.Background(),
+				cmd.Context(),
 				opts.Org,
 				opts.WfId,
 				opts.WfgGrp,
-				&sggosdk.WorkflowRun{
-					TerraformAction: &sggosdk.TerraformAction{
-						Action: sggosdk.ActionEnumDestroy.Ptr(),
-					},
-				},
+				request,
 			)
 			if err != nil {
-				cmd.Println(err)
-				os.Exit(-1)
+				return clierrors.Wrap(err, "Failed to destroy workflow %s", opts.WfId)
 			}
-			if opts.OutputJson {
-				cmd.Println(response)
+			if printer.Structured() && !opts.Wait {
+				if err := printer.Print(response, output.WorkflowRun); err != nil {
+					return err
+				}
 			}
-			cmd.Println("Workflow destroy run successfully.")
-			workflowRunPath := DASHBOARD_URL +
-				"/orgs/" +
-				opts.Org +
-				"/wfgrps/" +
-				opts.WfgGrp +
-				"/wfs/" +
-				opts.WfId +
-				"?tab=runs"
-			cmd.Println("To view the workflow runs, please visit the following URL:")
-			cmd.Println(workflowRunPath)
+			printer.Println("Workflow destroy run successfully.")
+			workflowRunPath := dashboard.Default().WorkflowRuns(opts.Org, opts.WfgGrp, opts.WfId)
+			printer.Println("To view the workflow runs, please visit the following URL:")
+			printer.Println(workflowRunPath)
+			if opts.Wait {
+				run, err := wait.RunID(response)
+				if err != nil {
+					return err
+				}
+				return wait.ForRun(cmd.Context(), c, printer, opts.Org, opts.WfgGrp, opts.WfId, run, &opts.Options)
+			}
+			return nil
 		},
 	}
 
 	destroyCmd.Flags().String("workflow-id", "", "The workflow ID to retrieve.")
 	destroyCmd.MarkFlagRequired("workflow-id")
-
-	destroyCmd.Flags().BoolVar(&opts.OutputJson, "output-json", false, "Output execution response as json to STDIN.")
+	destroyCmd.Flags().BoolVar(&opts.Wait, "wait", false, "Wait for the workflow run to finish. The exit code reflects its final status.")
+	wait.AddFlags(destroyCmd, &opts.Options)
+	runs.AddGuardFlags(destroyCmd, &opts.Guard)
+	overrides.AddTargetingFlags(destroyCmd, &opts.Targeting)
+	destroyCmd.Flags().BoolVarP(&opts.Targeting.Yes, "yes", "y", false, "Do not ask for a confirmation of a targeted run.")
 
 	return destroyCmd
 }
@@ -0,0 +1,121 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/export"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/update"
+	"github.com/StackGuardian/sg-cli/jsondiff"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+type RunOptions struct {
+	Org     string
+	WfgGrp  string
+	WfId    string
+	Color   string
+	Payload string
+}
+
+func NewDiffCmd(c *client.Client) *cobra.Command {
+	opts := &RunOptions{}
+	// diffCmd represents the diff command
+	var diffCmd = &cobra.Command{
+		Use:   "diff <payload.json>",
+		Short: "Compare a workflow payload with the live workflow",
+		Long: `Compare a workflow payload with the live workflow and print what workflow update would change.
+Only the fields of the payload are compared, fields set by the server and the order of keys are ignored.
+Environment variables are matched by name, values that look like secrets are redacted.
+The exit code is 0 when the workflow matches the payload and 1 when it differs.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
+			opts.Org = cmd.Parent().PersistentFlags().Lookup("org").Value.String()
+			opts.WfgGrp = cmd.Parent().PersistentFlags().Lookup("workflow-group").Value.String()
+			opts.Payload = args[0]
+			if opts.Color != "auto" && opts.Color != "always" && opts.Color != "never" {
+				return clierrors.New(clierrors.Usage, "invalid --color %q, expected auto, always or never", opts.Color)
+			}
+
+			// Exit code 1 is drift, the failures of the command itself must not use it
+			data, err := os.ReadFile(opts.Payload)
+			if err != nil {
+				return &clierrors.Error{Category: clierrors.Usage, Message: "Failed to read workflow payload", Err: err}
+			}
+			if _, err := update.Decode(data); err != nil {
+				return err
+			}
+			local, err := export.Payload(string(data))
+			if err != nil {
+				return &clierrors.Error{Category: clierrors.Validation, Message: "Error while unmarshalling Workflow payload", Err: err}
+			}
+			live, err := export.Read(cmd.Context(), c, opts.Org, opts.WfgGrp, opts.WfId)
+			if err != nil {
+				return err
+			}
+
+			changes := Compare(live, local)
+			if printer.Structured() {
+				if err := printer.Print(map[string]interface{}{"workflow": opts.WfId, "msg": changes}, output.WorkflowDiff); err != nil {
+					return err
+				}
+			} else if len(changes) == 0 {
+				printer.Println(">> Workflow " + opts.WfId + " matches " + opts.Payload + ".")
+			} else {
+				printer.Println(">> Workflow " + opts.WfId + " differs from " + opts.Payload + ":")
+				if color(opts.Color, printer.Text()) {
+					fmt.Fprintln(printer.Text(), jsondiff.RenderColor(changes))
+				} else {
+					fmt.Fprintln(printer.Text(), jsondiff.Render(changes))
+				}
+			}
+			if len(changes) > 0 {
+				return clierrors.New(clierrors.Drift, "workflow %s differs from %s in %d places", opts.WfId, opts.Payload, len(changes))
+			}
+			return nil
+		},
+	}
+
+	diffCmd.Flags().StringVar(&opts.WfId, "workflow-id", "", "The workflow ID to compare.")
+	diffCmd.MarkFlagRequired("workflow-id")
+	diffCmd.Flags().StringVar(&opts.Color, "color", "auto", "Color the differences: auto, always or never. auto colors them on a terminal unless NO_COLOR is set.")
+
+	return diffCmd
+}
+
+// Compare returns the differences from the live workflow to the payload, for the fields of the payload only.
+// The values of secrets are redacted.
+func Compare(live, payload map[string]interface{}) []jsondiff.Change {
+	before := map[string]interface{}{}
+	for field := range payload {
+		if value, ok := live[field]; ok {
+			before[field] = value
+		}
+	}
+	return jsondiff.Mask(jsondiff.CompareBy(before, payload, jsondiff.Identities...))
+}
+
+// color reports whether the differences written to w are colored
+func color(mode string, w io.Writer) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if _, ok := os.LookupEnv("NO_COLOR"); ok || strings.EqualFold(os.Getenv("TERM"), "dumb") {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
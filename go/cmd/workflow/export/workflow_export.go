@@ -0,0 +1,187 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/tfstate"
+	"github.com/StackGuardian/sg-cli/output"
+	sggosdk "github.com/StackGuardian/sg-sdk-go"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+)
+
+// Fields are the fields of a workflow that workflow create accepts. Server fields such as ResourceId, CreatedAt
+// or LatestWfrunStatus and the results of runs such as TfStateCleaned or TfDrift are not exported.
+var Fields = []string{"ResourceName", "Description", "Tags", "IsActive", "WfType", "WfStepsConfig", "TerraformConfig",
+	"EnvironmentVariables", "DeploymentPlatformConfig", "VCSConfig", "UserSchedules", "GitHubComSync", "MiniSteps",
+	"Approvers", "NumberOfApprovalsRequired", "RunnerConstraints", "UserJobCPU", "UserJobMemory", "CacheConfig", "ContextTags"}
+
+type RunOptions struct {
+	Org      string
+	WfgGrp   string
+	WfId     string
+	All      bool
+	File     string
+	StateDir string
+}
+
+func NewExportCmd(c *client.Client) *cobra.Command {
+	opts := &RunOptions{}
+	// exportCmd represents the export command
+	var exportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export workflows as payloads for workflow create",
+		Long: `Export a workflow as a JSON payload that workflow create accepts, without the fields set by the server.
+With --all, every workflow of the workflow group is exported as an array for workflow create --bulk, with
+CLIConfiguration.WorkflowGroup set. --state-dir also downloads the Terraform state of each workflow and sets
+CLIConfiguration.TfStateFilePath.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
+			if printer.Structured() {
+				return clierrors.New(clierrors.Usage, "workflow export prints a JSON payload and does not support --output")
+			}
+			opts.Org = cmd.Parent().PersistentFlags().Lookup("org").Value.String()
+			opts.WfgGrp = cmd.Parent().PersistentFlags().Lookup("workflow-group").Value.String()
+			if opts.All == (opts.WfId != "") {
+				return clierrors.New(clierrors.Usage, "pass either --workflow-id or --all")
+			}
+			if opts.StateDir != "" && !opts.All {
+				return clierrors.New(clierrors.Usage, "--state-dir requires --all, only workflow create --bulk uploads state files")
+			}
+
+			var exported interface{}
+			if opts.All {
+				workflows, err := exportAll(cmd, c, opts)
+				if err != nil {
+					return err
+				}
+				exported = workflows
+			} else {
+				workflow, err := Read(cmd.Context(), c, opts.Org, opts.WfgGrp, opts.WfId)
+				if err != nil {
+					return err
+				}
+				exported = workflow
+			}
+
+			payload, err := json.MarshalIndent(exported, "", "  ")
+			if err != nil {
+				return err
+			}
+			if opts.File == "" {
+				_, err = cmd.OutOrStdout().Write(append(payload, '\n'))
+				return err
+			}
+			// The payloads may hold secrets such as environment variables, only the owner can read them
+			if err := os.WriteFile(opts.File, append(payload, '\n'), 0o600); err != nil {
+				return clierrors.Wrap(err, "Failed to write %s", opts.File)
+			}
+			printer.Println(">> Exported to " + opts.File)
+			return nil
+		},
+	}
+
+	exportCmd.Flags().StringVar(&opts.WfId, "workflow-id", "", "The workflow ID to export.")
+	exportCmd.Flags().BoolVar(&opts.All, "all", false, "Export all workflows of the workflow group as a payload for workflow create --bulk.")
+	exportCmd.Flags().StringVar(&opts.File, "file", "", "Write the payload to this file instead of stdout.")
+	exportCmd.Flags().StringVar(&opts.StateDir, "state-dir", "", "Download the Terraform state of each workflow to <dir>/<workflow>.tfstate and set TfStateFilePath. Requires --all.")
+
+	return exportCmd
+}
+
+// Read returns the payload of an existing workflow
+func Read(ctx context.Context, c *client.Client, org, wfGrp, wf string) (map[string]interface{}, error) {
+	response, err := c.Workflows.ReadWorkflow(ctx, org, wf, wfGrp)
+	if err != nil {
+		return nil, clierrors.Wrap(err, "Failed to read workflow %s", wf)
+	}
+	payload, err := Payload(response.Msg.String())
+	if err != nil {
+		return nil, clierrors.Wrap(err, "Failed to decode workflow %s", wf)
+	}
+	return payload, nil
+}
+
+// Payload keeps the Fields of a workflow returned by the API
+func Payload(workflow string) (map[string]interface{}, error) {
+	var read map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(workflow)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&read); err != nil {
+		return nil, err
+	}
+	payload := map[string]interface{}{}
+	for _, field := range Fields {
+		if value, ok := read[field]; ok && value != nil {
+			payload[field] = value
+		}
+	}
+	return payload, nil
+}
+
+// exportAll returns the payloads of all workflows of the workflow group for workflow create --bulk
+func exportAll(cmd *cobra.Command, c *client.Client, opts *RunOptions) ([]map[string]interface{}, error) {
+	if opts.StateDir != "" {
+		if err := os.MkdirAll(opts.StateDir, 0o700); err != nil {
+			return nil, clierrors.Wrap(err, "Failed to create %s", opts.StateDir)
+		}
+	}
+	workflows := []map[string]interface{}{}
+	request := &sggosdk.ListAllWorkflowsRequest{}
+	for {
+		page, err := c.Workflows.ListAllWorkflows(cmd.Context(), opts.Org, opts.WfgGrp, request)
+		if err != nil {
+			return nil, clierrors.Wrap(err, "Failed to list workflows")
+		}
+		for _, item := range page.Msg {
+			workflow, err := Read(cmd.Context(), c, opts.Org, opts.WfgGrp, item.ResourceName)
+			if err != nil {
+				return nil, err
+			}
+			configuration := map[string]interface{}{"WorkflowGroup": map[string]interface{}{"name": opts.WfgGrp}}
+			if opts.StateDir != "" {
+				statePath := filepath.Join(opts.StateDir, item.ResourceName+".tfstate")
+				downloaded, err := downloadTfState(cmd.Context(), c, opts.Org, opts.WfgGrp, item.ResourceName, statePath)
+				if err != nil {
+					return nil, clierrors.Wrap(err, "Failed to download the state of workflow %s", item.ResourceName)
+				}
+				if downloaded {
+					configuration["TfStateFilePath"] = statePath
+				} else {
+					cmd.PrintErrln(">> [WARNING] Workflow " + item.ResourceName + " has no state, TfStateFilePath is not set.")
+				}
+			}
+			workflow["CLIConfiguration"] = configuration
+			workflows = append(workflows, workflow)
+		}
+		if page.Lastevaluatedkey == "" {
+			return workflows, nil
+		}
+		request.Lastevaluatedkey = sggosdk.String(page.Lastevaluatedkey)
+	}
+}
+
+// downloadTfState writes the Terraform state of a workflow to path, it reports false when the workflow has no state.
+// The state holds the secrets of the resources, the file is only readable by the owner.
+func downloadTfState(ctx context.Context, c *client.Client, org, wfGrp, wf, path string) (bool, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return false, err
+	}
+	downloaded, err := tfstate.Download(ctx, c, org, wfGrp, wf, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if !downloaded {
+		os.Remove(path)
+	}
+	return downloaded, err
+}
@@ -2,15 +2,41 @@ package list
 
 import (
 	"context"
-	"os"
+	"encoding/base64"
+	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
 
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/output"
 	sggosdk "github.com/StackGuardian/sg-sdk-go"
 	"github.com/StackGuardian/sg-sdk-go/client"
 	"github.com/spf13/cobra"
 )
 
+// SortOrders are the values of --sort-by
+var SortOrders = []string{"name", "modified", "status"}
+
 type RunOptions struct {
-	OutputJson bool
+	Org        string
+	WfgGrp     string
+	Types      []string
+	Status     []string
+	Tags       []string
+	RunnerType string
+	Name       string
+	NameRegex  string
+	SortBy     string
+	Limit      int
+	PageToken  string
+}
+
+// filter holds the parsed filters of the list command
+type filter struct {
+	*RunOptions
+	nameRegex *regexp.Regexp
 }
 
 func NewListCmd(c *client.Client) *cobra.Command {
@@ -19,31 +45,232 @@ func NewListCmd(c *client.Client) *cobra.Command {
 	var listCmd = &cobra.Command{
 		Use:   "list",
 		Short: "List all workflows",
-		Long:  `List all workflows`,
-		Run: func(cmd *cobra.Command, args []string) {
-			response, err := c.Workflows.ListAllWorkflows(
-				context.Background(),
-				cmd.Parent().Flags().Lookup("org").Value.String(),
-				cmd.Parent().Flags().Lookup("workflow-group").Value.String(),
-				&sggosdk.ListAllWorkflowsRequest{},
-			)
+		Long: `List the workflows of a workflow group with their type, latest run status, tags and last modification.
+All pages are fetched, from --page-token if it is set, until --limit workflows matched the filters.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
 			if err != nil {
-				cmd.Println(err)
-				os.Exit(-1)
+				return err
+			}
+			opts.Org = cmd.Parent().Flags().Lookup("org").Value.String()
+			opts.WfgGrp = cmd.Parent().Flags().Lookup("workflow-group").Value.String()
+
+			f := &filter{RunOptions: opts}
+			if opts.Limit < 0 {
+				return clierrors.New(clierrors.Usage, "--limit must be at least 1, or 0 to list all workflows")
+			}
+			if opts.SortBy != "" && !contains(SortOrders, opts.SortBy) {
+				return clierrors.New(clierrors.Usage, "invalid --sort-by %q, expected one of %s", opts.SortBy, strings.Join(SortOrders, ", "))
+			}
+			if opts.RunnerType != "" && opts.RunnerType != string(sggosdk.RunnerConstraintsTypeEnumShared) &&
+				opts.RunnerType != string(sggosdk.RunnerConstraintsTypeEnumPrivate) {
+				return clierrors.New(clierrors.Usage, "invalid --runner-type %q, expected shared or private", opts.RunnerType)
+			}
+			if _, err := path.Match(opts.Name, ""); err != nil {
+				return clierrors.New(clierrors.Usage, "invalid --name: %s", err)
+			}
+			if opts.NameRegex != "" {
+				if f.nameRegex, err = regexp.Compile(opts.NameRegex); err != nil {
+					return clierrors.New(clierrors.Usage, "invalid --name-regex: %s", err)
+				}
 			}
 
-			if opts.OutputJson {
-				cmd.Println(response)
+			// Sorting needs all workflows, the limit then applies to the sorted list
+			stopAtLimit := opts.Limit > 0 && opts.SortBy == ""
+			matched := []*sggosdk.GeneratedWorkflowsListAllMsg{}
+			truncated, next := false, ""
+			start := decodePageToken(opts.PageToken)
+			token, skip := start.Key, start.Skip
+			for !truncated {
+				request := &sggosdk.ListAllWorkflowsRequest{}
+				if token != "" {
+					request.Lastevaluatedkey = sggosdk.String(token)
+				}
+				page, err := c.Workflows.ListAllWorkflows(cmd.Context(), opts.Org, opts.WfgGrp, request)
+				if err != nil {
+					return clierrors.Wrap(err, "Failed to list workflows")
+				}
+				for i, workflow := range page.Msg {
+					if i < skip {
+						continue
+					}
+					if stopAtLimit && len(matched) == opts.Limit {
+						// The listing continues inside this page, after the workflows already read
+						truncated, next = true, pageToken{Key: token, Skip: i}.String()
+						break
+					}
+					ok, err := f.matches(cmd.Context(), c, workflow)
+					if err != nil {
+						return err
+					}
+					if ok {
+						matched = append(matched, workflow)
+					}
+				}
+				skip = 0
+				if truncated || page.Lastevaluatedkey == "" {
+					break
+				}
+				token = page.Lastevaluatedkey
+				if stopAtLimit && len(matched) == opts.Limit {
+					truncated, next = true, token
+				}
 			}
 
-			for _, workflow := range response.Msg {
-				cmd.Println("> Workflow Name: ", workflow.ResourceName)
-				cmd.Println("Description: ", workflow.Description)
-				//New line for formatting
-				cmd.Println()
+			Sort(matched, opts.SortBy)
+			if opts.Limit > 0 && len(matched) > opts.Limit {
+				matched, truncated = matched[:opts.Limit], true
+			}
+			items := make([]json.RawMessage, 0, len(matched))
+			for _, workflow := range matched {
+				items = append(items, json.RawMessage(workflow.String()))
+			}
+			doc := map[string]interface{}{"msg": items}
+			if next != "" {
+				doc["lastevaluatedkey"] = next
+			}
+			if err := printer.PrintDefault(doc, output.Workflow, "table"); err != nil {
+				return err
 			}
+			switch {
+			case next != "":
+				cmd.PrintErrf(">> Showing the first %d matching workflows, use --limit to list more or --page-token %s to continue.\n", opts.Limit, next)
+			case truncated:
+				cmd.PrintErrf(">> Showing the first %d matching workflows, use --limit to list more.\n", opts.Limit)
+			}
+			return nil
 		},
 	}
-	listCmd.Flags().BoolVar(&opts.OutputJson, "output-json", false, "Output execution response as json to STDIN.")
+
+	listCmd.Flags().StringSliceVar(&opts.Types, "type", nil, "Only list workflows of these types, e.g. TERRAFORM,OPENTOFU.")
+	listCmd.Flags().StringSliceVar(&opts.Status, "status", nil, "Only list workflows whose latest run has one of these statuses, e.g. ERRORED,FAILED.")
+	listCmd.Flags().StringArrayVar(&opts.Tags, "tag", nil, "Only list workflows with this tag. Can be repeated, workflows must have all the tags.")
+	listCmd.Flags().StringVar(&opts.RunnerType, "runner-type", "", "Only list workflows that run on shared or private runners.")
+	listCmd.Flags().StringVar(&opts.Name, "name", "", "Only list workflows whose name matches this glob, e.g. 'team-a-*'.")
+	listCmd.Flags().StringVar(&opts.NameRegex, "name-regex", "", "Only list workflows whose name matches this regular expression.")
+	listCmd.Flags().StringVar(&opts.SortBy, "sort-by", "", "Sort the workflows by "+strings.Join(SortOrders, ", ")+". modified lists the latest first.")
+	listCmd.Flags().IntVar(&opts.Limit, "limit", 0, "Maximum number of workflows to list, 0 lists all.")
+	listCmd.Flags().StringVar(&opts.PageToken, "page-token", "", "Start listing at this page token, as printed when --limit stops the listing.")
+
 	return listCmd
 }
+
+// matches reports whether a workflow passes the filters. The runner type is read from the workflow
+// when the list does not include it.
+func (f *filter) matches(ctx context.Context, c *client.Client, workflow *sggosdk.GeneratedWorkflowsListAllMsg) (bool, error) {
+	if len(f.Types) > 0 && !containsFold(f.Types, workflow.WfType) {
+		return false, nil
+	}
+	if len(f.Status) > 0 && !containsFold(f.Status, workflow.LatestWfrunStatus) {
+		return false, nil
+	}
+	for _, tag := range f.Tags {
+		found := false
+		for _, t := range workflow.Tags {
+			found = found || t == tag
+		}
+		if !found {
+			return false, nil
+		}
+	}
+	if f.Name != "" {
+		if ok, _ := path.Match(f.Name, workflow.ResourceName); !ok {
+			return false, nil
+		}
+	}
+	if f.nameRegex != nil && !f.nameRegex.MatchString(workflow.ResourceName) {
+		return false, nil
+	}
+	if f.RunnerType == "" {
+		return true, nil
+	}
+	runnerType, ok := RunnerType(workflow.String())
+	if !ok {
+		response, err := c.Workflows.ReadWorkflow(ctx, f.Org, workflow.ResourceName, f.WfgGrp)
+		if err != nil {
+			return false, clierrors.Wrap(err, "Failed to read workflow %s", workflow.ResourceName)
+		}
+		runnerType, _ = RunnerType(response.Msg.String())
+	}
+	return runnerType == f.RunnerType, nil
+}
+
+// pageToken is the position printed by --limit, the key of a page and the number of its workflows
+// already listed. A position at the start of a page is printed as the key of the API.
+type pageToken struct {
+	Key  string `json:"key,omitempty"`
+	Skip int    `json:"skip,omitempty"`
+}
+
+func (p pageToken) String() string {
+	if p.Skip == 0 {
+		return p.Key
+	}
+	data, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodePageToken reads a --page-token, tokens that are not a position are keys of the API
+func decodePageToken(token string) pageToken {
+	var p pageToken
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || json.Unmarshal(data, &p) != nil || p.Skip <= 0 {
+		return pageToken{Key: token}
+	}
+	return p
+}
+
+// RunnerType returns the runner type of a workflow, shared when it has no runner constraints.
+// It reports false when the workflow JSON has no RunnerConstraints field.
+func RunnerType(workflow string) (string, bool) {
+	var w struct {
+		RunnerConstraints *struct {
+			Type string `json:"type"`
+		}
+	}
+	if err := json.Unmarshal([]byte(workflow), &w); err != nil || w.RunnerConstraints == nil {
+		return string(sggosdk.RunnerConstraintsTypeEnumShared), false
+	}
+	if w.RunnerConstraints.Type == "" {
+		return string(sggosdk.RunnerConstraintsTypeEnumShared), true
+	}
+	return w.RunnerConstraints.Type, true
+}
+
+// Sort orders workflows by name, by latest modification first or by latest run status and name.
+// Other orders keep the order of the API.
+func Sort(workflows []*sggosdk.GeneratedWorkflowsListAllMsg, by string) {
+	sort.SliceStable(workflows, func(i, j int) bool {
+		a, b := workflows[i], workflows[j]
+		switch by {
+		case "name":
+			return a.ResourceName < b.ResourceName
+		case "modified":
+			return a.ModifiedAt > b.ModifiedAt
+		case "status":
+			if a.LatestWfrunStatus != b.LatestWfrunStatus {
+				return a.LatestWfrunStatus < b.LatestWfrunStatus
+			}
+			return a.ResourceName < b.ResourceName
+		}
+		return false
+	})
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,425 @@
+package overrides
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/output"
+	sggosdk "github.com/StackGuardian/sg-sdk-go"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// Options are the flags that change the inputs of a single run of a workflow
+type Options struct {
+	Env         []string
+	EnvFile     string
+	TfVars      []string
+	VarFile     string
+	VcsRef      string
+	RunnerGroup string
+	RunPayload  string
+	DryRun      bool
+	Targeting
+}
+
+// Targeting are the flags that limit a run to some resources, they become Terraform plan options of the run
+type Targeting struct {
+	Targets     []string
+	Replace     []string
+	RefreshOnly bool
+	// Yes skips the confirmation of a targeted apply or destroy
+	Yes bool
+}
+
+// AddFlags adds the override flags and --dry-run to cmd
+func AddFlags(cmd *cobra.Command, opts *Options) {
+	cmd.Flags().StringArrayVar(&opts.Env, "env", nil, "Set an environment variable for this run, KEY=VALUE. Can be repeated.")
+	cmd.Flags().StringVar(&opts.EnvFile, "env-file", "", "Read environment variables for this run from a file of KEY=VALUE lines. --env takes precedence.")
+	cmd.Flags().StringArrayVar(&opts.TfVars, "tf-var", nil, "Set a Terraform variable for this run, NAME=VALUE. JSON values such as numbers, booleans and lists keep their type. Can be repeated.")
+	cmd.Flags().StringVar(&opts.VarFile, "var-file", "", "Read Terraform variables for this run from a .tfvars or .tfvars.json file. --tf-var takes precedence.")
+	cmd.Flags().StringVar(&opts.VcsRef, "vcs-ref", "", "Run the code of this branch, tag or commit instead of the one of the workflow.")
+	cmd.Flags().StringVar(&opts.RunnerGroup, "runner-group", "", "Run on this private runner group.")
+	cmd.Flags().StringVar(&opts.RunPayload, "run-payload", "", "Path to a JSON file with the body of the workflow run. The other flags are applied on top of it.")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Print the workflow run request without starting the run.")
+	AddTargetingFlags(cmd, &opts.Targeting)
+}
+
+// AddTargetingFlags adds --target, --replace and --refresh-only to cmd
+func AddTargetingFlags(cmd *cobra.Command, opts *Targeting) {
+	cmd.Flags().StringArrayVar(&opts.Targets, "target", nil, "Limit the run to this resource address and its dependencies, like terraform -target. Can be repeated.")
+	cmd.Flags().StringArrayVar(&opts.Replace, "replace", nil, "Force the replacement of this resource address, like terraform -replace. Can be repeated.")
+	cmd.Flags().BoolVar(&opts.RefreshOnly, "refresh-only", false, "Only update the state to match the real infrastructure, like terraform -refresh-only.")
+}
+
+// Targeted reports whether the run is limited to some resources
+func (t *Targeting) Targeted() bool {
+	return len(t.Targets) > 0 || len(t.Replace) > 0 || t.RefreshOnly
+}
+
+// PlanOptions returns the Terraform plan options of the targeting flags, e.g. "-target=aws_instance.web"
+func (t *Targeting) PlanOptions() []string {
+	options := []string{}
+	for _, target := range t.Targets {
+		options = append(options, "-target="+target)
+	}
+	for _, address := range t.Replace {
+		options = append(options, "-replace="+address)
+	}
+	if t.RefreshOnly {
+		options = append(options, "-refresh-only")
+	}
+	return options
+}
+
+// validate checks the targeting flags for an action, Terraform rejects the same combinations
+func (t *Targeting) validate(action sggosdk.ActionEnum) error {
+	for _, address := range append(append([]string{}, t.Targets...), t.Replace...) {
+		if strings.TrimSpace(address) == "" || strings.ContainsAny(address, " \t\n") {
+			return clierrors.New(clierrors.Usage, "invalid resource address %q, addresses cannot be empty or contain spaces", address)
+		}
+	}
+	if t.RefreshOnly && len(t.Replace) > 0 {
+		return clierrors.New(clierrors.Usage, "--replace cannot be used with --refresh-only")
+	}
+	if t.RefreshOnly && action == sggosdk.ActionEnumDestroy {
+		return clierrors.New(clierrors.Usage, "--refresh-only cannot be used with destroy")
+	}
+	return nil
+}
+
+// Confirm describes a targeted run and asks whether to start it, unless --yes was passed.
+// Runs without targeting flags start without a confirmation.
+func Confirm(cmd *cobra.Command, wf string, action sggosdk.ActionEnum, opts *Targeting) error {
+	if !opts.Targeted() {
+		return nil
+	}
+	errOut := cmd.ErrOrStderr()
+	fmt.Fprintf(errOut, ">> [WARNING] This %s of workflow %s is a targeted run:\n", action, wf)
+	for _, option := range opts.PlanOptions() {
+		fmt.Fprintf(errOut, "     %s\n", option)
+	}
+	if opts.RefreshOnly {
+		fmt.Fprintln(errOut, "   Only the state is updated, no resource is changed.")
+	} else {
+		fmt.Fprintln(errOut, "   Resources outside of the targets are not changed and may no longer match the configuration.")
+	}
+	if opts.Yes {
+		return nil
+	}
+	in := cmd.InOrStdin()
+	if f, ok := in.(*os.File); ok && !term.IsTerminal(int(f.Fd())) {
+		return clierrors.New(clierrors.Usage, "cannot ask for a confirmation of the targeted run without a terminal, pass --yes to start it")
+	}
+	fmt.Fprintf(errOut, "Start the targeted %s? [y/N]: ", action)
+	line, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return nil
+	}
+	return clierrors.New(clierrors.General, "targeted %s of workflow %s not started", action, wf)
+}
+
+// Request builds the body of a workflow run from --run-payload, the action and the overrides.
+// The workflow is only read when an override changes a part of its configuration that the payload does not set.
+func Request(ctx context.Context, c *client.Client, org, wfGrp, wf string, action sggosdk.ActionEnum, opts *Options) (*sggosdk.WorkflowRun, error) {
+	if err := opts.Targeting.validate(action); err != nil {
+		return nil, err
+	}
+	payload := map[string]interface{}{}
+	if opts.RunPayload != "" {
+		data, err := os.ReadFile(opts.RunPayload)
+		if err != nil {
+			return nil, clierrors.Wrap(err, "Failed to read run payload")
+		}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, &clierrors.Error{Category: clierrors.Validation, Message: "Please provide a valid JSON payload. The run payload should be an object", Err: err}
+		}
+	}
+	// The command decides the action, a run payload cannot turn an apply into a destroy or a plan into an apply
+	if value, ok := object(payload, "TerraformAction")["action"]; !ok || !compatible(action, value) {
+		if ok {
+			return nil, clierrors.New(clierrors.Usage, "the run payload sets TerraformAction.action to %v, remove it or use the workflow command of that action", value)
+		}
+		object(payload, "TerraformAction")["action"] = string(action)
+	}
+
+	env, err := environment(opts)
+	if err != nil {
+		return nil, err
+	}
+	tfVars, err := terraformVariables(opts)
+	if err != nil {
+		return nil, err
+	}
+	var workflow map[string]interface{}
+	// current returns a field of the payload, or of the workflow configuration when the payload does not set it
+	current := func(field string) (interface{}, error) {
+		if value, ok := payload[field]; ok {
+			return value, nil
+		}
+		if workflow == nil {
+			response, err := c.Workflows.ReadWorkflow(ctx, org, wf, wfGrp)
+			if err != nil {
+				return nil, clierrors.Wrap(err, "Failed to read workflow %s", wf)
+			}
+			if err := json.Unmarshal([]byte(response.Msg.String()), &workflow); err != nil {
+				return nil, clierrors.Wrap(err, "Failed to decode workflow %s", wf)
+			}
+		}
+		return workflow[field], nil
+	}
+
+	if len(env) > 0 {
+		variables, err := current("EnvironmentVariables")
+		if err != nil {
+			return nil, err
+		}
+		payload["EnvironmentVariables"] = setEnvironment(variables, env)
+	}
+	if opts.VcsRef != "" || len(tfVars) > 0 {
+		value, err := current("VCSConfig")
+		if err != nil {
+			return nil, err
+		}
+		vcs, _ := value.(map[string]interface{})
+		if vcs == nil {
+			vcs = map[string]interface{}{}
+		}
+		if opts.VcsRef != "" {
+			source, _ := object(vcs, "iacVCSConfig")["customSource"].(map[string]interface{})
+			if source == nil {
+				return nil, clierrors.New(clierrors.Usage, "--vcs-ref requires a workflow with a VCS source, workflow %s uses a template", wf)
+			}
+			object(source, "config")["ref"] = opts.VcsRef
+		}
+		if len(tfVars) > 0 {
+			inputs := object(vcs, "iacInputData")
+			switch inputs["schemaType"] {
+			case string(sggosdk.IacInputDataSchemaTypeEnumRawHcl):
+				return nil, clierrors.New(clierrors.Usage, "workflow %s takes its Terraform variables as HCL, set them with --run-payload", wf)
+			case nil, "", string(sggosdk.IacInputDataSchemaTypeEnumNone):
+				inputs["schemaType"] = string(sggosdk.IacInputDataSchemaTypeEnumRawJson)
+			}
+			data := object(inputs, "data")
+			for name, value := range tfVars {
+				data[name] = value
+			}
+		}
+		payload["VCSConfig"] = vcs
+	}
+	if opts.Targeted() {
+		value, err := current("TerraformConfig")
+		if err != nil {
+			return nil, err
+		}
+		config, _ := value.(map[string]interface{})
+		if config == nil {
+			config = map[string]interface{}{}
+		}
+		planOptions, _ := config["terraformPlanOptions"].(string)
+		config["terraformPlanOptions"] = strings.TrimSpace(strings.Join(append([]string{planOptions}, opts.PlanOptions()...), " "))
+		payload["TerraformConfig"] = config
+	}
+	if opts.RunnerGroup != "" {
+		payload["RunnerConstraints"] = map[string]interface{}{
+			"type":  string(sggosdk.RunnerConstraintsTypeEnumPrivate),
+			"names": []interface{}{opts.RunnerGroup},
+		}
+	}
+	return decode(payload)
+}
+
+// Print prints the request of a --dry-run
+func Print(printer *output.Printer, request *sggosdk.WorkflowRun) error {
+	requestJson, err := json.MarshalIndent(request, "", "    ")
+	if err != nil {
+		return err
+	}
+	printer.Println(string(requestJson))
+	return nil
+}
+
+// decode validates a workflow run body against the SDK type
+func decode(payload map[string]interface{}) (*sggosdk.WorkflowRun, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, clierrors.Wrap(err, "Failed to encode the workflow run")
+	}
+	var request *sggosdk.WorkflowRun
+	if err := json.Unmarshal(data, &request); err != nil {
+		return nil, &clierrors.Error{Category: clierrors.Validation, Message: "Error while unmarshalling WorkflowRun payload", Err: err}
+	}
+	if unknown := request.GetExtraProperties(); len(unknown) > 0 {
+		fields := make([]string, 0, len(unknown))
+		for field := range unknown {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		return nil, clierrors.New(clierrors.Validation, "unknown fields in the workflow run payload: %s", strings.Join(fields, ", "))
+	}
+	if action := request.GetTerraformAction().GetAction(); action != nil {
+		if _, err := sggosdk.NewActionEnumFromString(string(*action)); err != nil {
+			return nil, clierrors.New(clierrors.Validation, "invalid TerraformAction.action %q in the workflow run payload", *action)
+		}
+	}
+	return request, nil
+}
+
+// planActions are the actions a run payload may set for workflow plan, none of them changes resources
+var planActions = []sggosdk.ActionEnum{sggosdk.ActionEnumPlan, sggosdk.ActionEnumPlanDestroy, sggosdk.ActionEnumPlanWithoutPolicy}
+
+// compatible reports whether a run payload may set value as the action of a command running action
+func compatible(action sggosdk.ActionEnum, value interface{}) bool {
+	if action != sggosdk.ActionEnumPlan {
+		return value == string(action)
+	}
+	for _, plan := range planActions {
+		if value == string(plan) {
+			return true
+		}
+	}
+	return false
+}
+
+// object returns the object at key in parent, adding an empty one if there is none
+func object(parent map[string]interface{}, key string) map[string]interface{} {
+	child, ok := parent[key].(map[string]interface{})
+	if !ok {
+		child = map[string]interface{}{}
+		parent[key] = child
+	}
+	return child
+}
+
+// assignment is a NAME=VALUE pair of --env or --tf-var
+type assignment struct {
+	name, value string
+}
+
+func parseAssignment(source, text string) (assignment, error) {
+	name, value, found := strings.Cut(text, "=")
+	if !found || strings.TrimSpace(name) == "" {
+		return assignment{}, clierrors.New(clierrors.Usage, "invalid %s %q, expected NAME=VALUE", source, text)
+	}
+	return assignment{strings.TrimSpace(name), value}, nil
+}
+
+// environment returns the variables of --env-file and --env in order, --env last
+func environment(opts *Options) ([]assignment, error) {
+	variables := []assignment{}
+	if opts.EnvFile != "" {
+		data, err := os.ReadFile(opts.EnvFile)
+		if err != nil {
+			return nil, clierrors.Wrap(err, "Failed to read env file")
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+			variable, err := parseAssignment(fmt.Sprintf("line %d of %s", line, opts.EnvFile), strings.TrimPrefix(text, "export "))
+			if err != nil {
+				return nil, err
+			}
+			if len(variable.value) >= 2 && strings.Contains(`"'`, variable.value[:1]) && variable.value[len(variable.value)-1] == variable.value[0] {
+				variable.value = variable.value[1 : len(variable.value)-1]
+			}
+			variables = append(variables, variable)
+		}
+	}
+	for _, env := range opts.Env {
+		variable, err := parseAssignment("--env", env)
+		if err != nil {
+			return nil, err
+		}
+		variables = append(variables, variable)
+	}
+	return variables, nil
+}
+
+// setEnvironment sets plain text variables in a list of environment variables, replacing those with the same name
+func setEnvironment(current interface{}, variables []assignment) []interface{} {
+	list, _ := current.([]interface{})
+	for _, variable := range variables {
+		entry := map[string]interface{}{
+			"kind":   string(sggosdk.EnvVarsKindEnumPlainText),
+			"config": map[string]interface{}{"varName": variable.name, "textValue": variable.value},
+		}
+		replaced := false
+		for i, existing := range list {
+			object, _ := existing.(map[string]interface{})
+			config, _ := object["config"].(map[string]interface{})
+			if config["varName"] == variable.name {
+				list[i] = entry
+				replaced = true
+			}
+		}
+		if !replaced {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// terraformVariables returns the variables of --var-file and --tf-var, --tf-var taking precedence
+func terraformVariables(opts *Options) (map[string]interface{}, error) {
+	variables := map[string]interface{}{}
+	if opts.VarFile != "" {
+		data, err := os.ReadFile(opts.VarFile)
+		if err != nil {
+			return nil, clierrors.Wrap(err, "Failed to read var file")
+		}
+		if strings.HasSuffix(opts.VarFile, ".json") {
+			if err := json.Unmarshal(data, &variables); err != nil {
+				return nil, &clierrors.Error{Category: clierrors.Validation, Message: "Please provide a valid JSON object in " + opts.VarFile, Err: err}
+			}
+		} else if err := parseTfVars(opts.VarFile, data, variables); err != nil {
+			return nil, err
+		}
+	}
+	for _, tfVar := range opts.TfVars {
+		variable, err := parseAssignment("--tf-var", tfVar)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(variable.value), &value); err != nil {
+			value = variable.value
+		}
+		variables[variable.name] = value
+	}
+	return variables, nil
+}
+
+// parseTfVars reads the NAME = VALUE lines of a .tfvars file. Values must fit on one line and be valid JSON,
+// e.g. strings, numbers, booleans and lists. Other files should be converted to .tfvars.json.
+func parseTfVars(name string, data []byte, variables map[string]interface{}) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "//") {
+			continue
+		}
+		variable, err := parseAssignment(fmt.Sprintf("line %d of %s", line, name), text)
+		if err != nil {
+			return err
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(variable.value)), &value); err != nil {
+			return clierrors.New(clierrors.Validation, "%s:%d: unsupported value for %s, only single line strings, numbers, booleans and lists are supported, use a .tfvars.json file for others",
+				name, line, variable.name)
+		}
+		variables[variable.name] = value
+	}
+	return nil
+}
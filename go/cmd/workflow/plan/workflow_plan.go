@@ -0,0 +1,233 @@
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/overrides"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/wait"
+	"github.com/StackGuardian/sg-cli/dashboard"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-cli/runs"
+	"github.com/StackGuardian/sg-cli/transport"
+	sggosdk "github.com/StackGuardian/sg-sdk-go"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+)
+
+type RunOptions struct {
+	Org              string
+	WfgGrp           string
+	WfId             string
+	DetailedExitCode bool
+	Interval         time.Duration
+	Overrides        overrides.Options
+}
+
+// Change is a resource change of a plan
+type Change struct {
+	Address string `json:"address"`
+	Action  string `json:"action"`
+}
+
+// Actions of a resource change, in the order of the summary
+const (
+	Add     = "add"
+	Update  = "change"
+	Replace = "replace"
+	Destroy = "destroy"
+)
+
+// symbols are the markers of the actions in the summary, like terraform plan
+var symbols = map[string]string{Add: "  +", Update: "  ~", Replace: "-/+", Destroy: "  -"}
+
+// descriptions complete the summary line of each action
+var descriptions = map[string]string{Add: "will be created", Update: "will be updated in-place", Replace: "must be replaced", Destroy: "will be destroyed"}
+
+func NewPlanCmd(c *client.Client) *cobra.Command {
+	opts := &RunOptions{}
+	// planCmd represents the plan command
+	var planCmd = &cobra.Command{
+		Use:   "plan",
+		Short: "Plan a workflow and summarize the changes",
+		Long: `Start a plan-only run of a workflow, wait for it and summarize the resources it would add, change,
+replace and destroy from the plan JSON artifact of the run.
+With --detailed-exitcode, the exit code is 0 when there are no changes, 2 when there are and 1 for any failure, like terraform plan.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
+			opts.Org = cmd.Parent().PersistentFlags().Lookup("org").Value.String()
+			opts.WfgGrp = cmd.Parent().PersistentFlags().Lookup("workflow-group").Value.String()
+			request, err := overrides.Request(cmd.Context(), c, opts.Org, opts.WfgGrp, opts.WfId, sggosdk.ActionEnumPlan, &opts.Overrides)
+			if err != nil {
+				return err
+			}
+			if opts.Overrides.DryRun {
+				return overrides.Print(printer, request)
+			}
+			response, err := c.WorkflowRuns.CreateWorkflowRun(cmd.Context(), opts.Org, opts.WfId, opts.WfgGrp, request)
+			if err != nil {
+				return clierrors.Wrap(err, "Failed to plan workflow %s", opts.WfId)
+			}
+			run, err := wait.RunID(response)
+			if err != nil {
+				return err
+			}
+
+			name := "Workflow run " + run
+			status, err := runs.Watch(cmd.Context(), func(ctx context.Context) (string, error) {
+				response, err := c.WorkflowRuns.ReadWorkflowRun(ctx, opts.Org, opts.WfId, opts.WfgGrp, run)
+				if err != nil {
+					return "", clierrors.Wrap(err, "Failed to read workflow run %s", run)
+				}
+				return response.Msg.GetLatestStatus(), nil
+			}, runs.WatchOptions{Name: name, Interval: opts.Interval, Log: printer.Println})
+			if err != nil {
+				return clierrors.Wrap(err, "Stopped waiting for workflow run %s", run)
+			}
+			if status != runs.Completed {
+				printer.Println("To view the workflow run, please visit the following URL:")
+				printer.Println(dashboard.Default().WorkflowRun(opts.Org, opts.WfgGrp, opts.WfId, run))
+				return runs.Outcome(name, status, nil)
+			}
+
+			changes, err := Changes(cmd.Context(), c, opts.Org, opts.WfgGrp, opts.WfId, run)
+			if err != nil {
+				return err
+			}
+			if printer.Structured() {
+				if err := printer.Print(map[string]interface{}{"run": run, "summary": Summary(changes), "msg": changes}, output.PlanChanges); err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintln(printer.Text(), Render(changes))
+			}
+			if opts.DetailedExitCode && len(changes) > 0 {
+				return clierrors.New(clierrors.Changes, "workflow run %s plans %d changes", run, len(changes))
+			}
+			return nil
+		},
+	}
+
+	planCmd.Flags().StringVar(&opts.WfId, "workflow-id", "", "The workflow ID to plan.")
+	planCmd.MarkFlagRequired("workflow-id")
+	planCmd.Flags().BoolVar(&opts.DetailedExitCode, "detailed-exitcode", false, "Exit with 0 when the plan has no changes, 2 when it has changes and 1 when it fails.")
+	planCmd.Flags().DurationVar(&opts.Interval, "poll-interval", runs.DefaultInterval, "Time between two status checks of the run.")
+	overrides.AddFlags(planCmd, &opts.Overrides)
+
+	return planCmd
+}
+
+// PlanArtifact is the name of the Terraform JSON plan among the artifacts of a run
+const PlanArtifact = "plan.json"
+
+// Changes downloads the plan JSON artifact of a run and returns its resource changes, without no-op and read changes
+func Changes(ctx context.Context, c *client.Client, org, wfGrp, wf, run string) ([]Change, error) {
+	list, err := c.Workflows.ListAllWorkflowArtifacts(ctx, org, wf, wfGrp)
+	if err != nil && !strings.Contains(err.Error(), "the server responded with nothing") {
+		return nil, clierrors.Wrap(err, "Failed to list the artifacts of workflow run %s", run)
+	}
+	artifactURL := ""
+	for name, artifact := range list.GetData().GetArtifacts() {
+		if strings.HasSuffix(name, "/wfruns/"+run+"/artifacts/"+PlanArtifact) {
+			artifactURL = artifact.GetUrl()
+		}
+	}
+	if artifactURL == "" {
+		return nil, clierrors.New(clierrors.NotFound, "workflow run %s has no %s artifact", run, PlanArtifact)
+	}
+	data, err := download(ctx, artifactURL)
+	if err != nil {
+		return nil, clierrors.Wrap(err, "Failed to download the plan of workflow run %s", run)
+	}
+
+	var plan struct {
+		FormatVersion   string `json:"format_version"`
+		ResourceChanges *[]struct {
+			Address string `json:"address"`
+			Change  struct {
+				Actions []string `json:"actions"`
+			} `json:"change"`
+		} `json:"resource_changes"`
+	}
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, clierrors.Wrap(err, "Failed to decode the plan of workflow run %s", run)
+	}
+	// Any other document would pass as a plan without changes
+	if plan.FormatVersion == "" || plan.ResourceChanges == nil {
+		return nil, clierrors.New(clierrors.Validation, "the %s artifact of workflow run %s is not a Terraform JSON plan", PlanArtifact, run)
+	}
+	changes := []Change{}
+	for _, resource := range *plan.ResourceChanges {
+		if action := actionOf(resource.Change.Actions); action != "" {
+			changes = append(changes, Change{Address: resource.Address, Action: action})
+		}
+	}
+	return changes, nil
+}
+
+// actionOf returns the summary action of the Terraform actions of a resource change
+func actionOf(actions []string) string {
+	switch strings.Join(actions, ",") {
+	case "create":
+		return Add
+	case "update":
+		return Update
+	case "delete,create", "create,delete":
+		return Replace
+	case "delete":
+		return Destroy
+	}
+	return ""
+}
+
+// Summary counts the changes by action
+func Summary(changes []Change) map[string]int {
+	summary := map[string]int{Add: 0, Update: 0, Replace: 0, Destroy: 0}
+	for _, change := range changes {
+		summary[change.Action]++
+	}
+	return summary
+}
+
+// Render renders changes like terraform plan
+func Render(changes []Change) string {
+	if len(changes) == 0 {
+		return "No changes. Your infrastructure matches the configuration."
+	}
+	b := &strings.Builder{}
+	fmt.Fprintln(b, "Terraform will perform the following actions:")
+	fmt.Fprintln(b)
+	for _, change := range changes {
+		fmt.Fprintf(b, "%s %s %s\n", symbols[change.Action], change.Address, descriptions[change.Action])
+	}
+	summary := Summary(changes)
+	fmt.Fprintf(b, "\nPlan: %d to add, %d to change, %d to replace, %d to destroy.",
+		summary[Add], summary[Update], summary[Replace], summary[Destroy])
+	return b.String()
+}
+
+// download fetches an artifact from its signed URL
+func download(ctx context.Context, artifactURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, artifactURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := transport.Client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, clierrors.New(clierrors.Server, "expected status code 200, got %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
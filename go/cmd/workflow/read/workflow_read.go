@@ -1,9 +1,8 @@
 package read
 
 import (
-	"context"
-	"os"
-
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/output"
 	"github.com/StackGuardian/sg-sdk-go/client"
 	"github.com/spf13/cobra"
 )
@@ -14,18 +13,21 @@ func NewReadCmd(c *client.Client) *cobra.Command {
 		Use:   "read",
 		Short: "Get details of a workflow",
 		Long:  `Get details of a workflow.`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
 			response, err := c.Workflows.ReadWorkflow(
-				context.Background(),
+				cmd.Context(),
 				cmd.Parent().Flags().Lookup("org").Value.String(),
 				cmd.Flags().Lookup("workflow-id").Value.String(),
 				cmd.Parent().Flags().Lookup("workflow-group").Value.String(),
 			)
 			if err != nil {
-				cmd.Println(err)
-				os.Exit(-1)
+				return clierrors.Wrap(err, "Failed to read workflow")
 			}
-			cmd.Println(response)
+			return printer.Print(response, output.Workflow)
 		},
 	}
 
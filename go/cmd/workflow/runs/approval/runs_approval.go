@@ -0,0 +1,281 @@
+package approval
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/runs/logs"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-cli/runs"
+	sggosdk "github.com/StackGuardian/sg-sdk-go"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// Pending is a workflow run waiting for an approval and the result of approving or rejecting it
+type Pending struct {
+	Workflow string `json:"workflow"`
+	Run      string `json:"run"`
+	Stack    string `json:"stack,omitempty"`
+	Status   string `json:"status"`
+	Plan     string `json:"plan"`
+	Result   string `json:"result,omitempty"`
+}
+
+type RunOptions struct {
+	Org      string
+	WfgGrp   string
+	WfId     string
+	StackId  string
+	StackRun string
+	Comment  string
+	Pending  bool
+	Yes      bool
+}
+
+func NewApproveCmd(c *client.Client) *cobra.Command {
+	return newCmd(c, true)
+}
+
+func NewRejectCmd(c *client.Client) *cobra.Command {
+	return newCmd(c, false)
+}
+
+func newCmd(c *client.Client, approve bool) *cobra.Command {
+	opts := &RunOptions{}
+	verb, past, title := "approve", "approved", "Approve"
+	if !approve {
+		verb, past, title = "reject", "rejected", "Reject"
+	}
+	var approvalCmd = &cobra.Command{
+		Use:   verb + " [run-id]",
+		Short: title + " workflow runs waiting for an approval",
+		Long: title + ` a workflow run in APPROVAL_REQUIRED.
+With --pending, every run waiting for an approval in the workflow group, or in the stack run given with
+--stack-id and --stack-run, is listed with its plan summary and the selected ones are ` + past + ` after
+a confirmation. --yes skips the confirmation and selects all of them.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
+			opts.Org = cmd.Flags().Lookup("org").Value.String()
+			opts.WfgGrp = cmd.Flags().Lookup("workflow-group").Value.String()
+
+			if len(args) == 1 {
+				if opts.Pending || opts.StackRun != "" {
+					return clierrors.New(clierrors.Usage, "a run ID cannot be used with --pending or --stack-run")
+				}
+				if opts.WfId == "" {
+					return clierrors.New(clierrors.Usage, "--workflow-id is required to %s a workflow run", verb)
+				}
+				p := &Pending{Workflow: opts.WfId, Run: args[0], Stack: opts.StackId, Status: runs.ApprovalRequired}
+				if err := resume(cmd.Context(), c, opts, p, approve); err != nil {
+					return clierrors.Wrap(err, "Failed to %s workflow run %s", verb, p.Run)
+				}
+				p.Result = past
+				printer.Println("Workflow run " + p.Run + " " + past + ".")
+				if printer.Structured() {
+					return printer.Print(map[string]interface{}{"msg": []*Pending{p}}, output.Approvals)
+				}
+				return nil
+			}
+
+			if !opts.Pending && opts.StackRun == "" {
+				return clierrors.New(clierrors.Usage, "pass a run ID, or --pending to %s the runs waiting for an approval", verb)
+			}
+			if opts.StackRun != "" && opts.StackId == "" {
+				return clierrors.New(clierrors.Usage, "--stack-run requires --stack-id")
+			}
+			pending, err := findPending(cmd.Context(), c, opts)
+			if err != nil {
+				return err
+			}
+			if len(pending) == 0 {
+				printer.Println(">> No workflow runs are waiting for an approval.")
+				if printer.Structured() {
+					return printer.Print(map[string]interface{}{"msg": pending}, output.Approvals)
+				}
+				return nil
+			}
+			for _, p := range pending {
+				p.Plan, err = planSummary(cmd.Context(), c, opts, p)
+				if err != nil {
+					cmd.PrintErrln(">> [WARNING] Failed to read the plan of workflow run " + p.Run + ": " + err.Error())
+				}
+			}
+
+			printer.Println(table(pending))
+			selected, err := confirm(cmd, pending, verb, title, opts.Yes)
+			if err != nil {
+				return err
+			}
+			failed := 0
+			for _, p := range pending {
+				p.Result = "skipped"
+			}
+			for _, p := range selected {
+				if err := resume(cmd.Context(), c, opts, p, approve); err != nil {
+					p.Result = "failed: " + clierrors.From(err).Error()
+					cmd.PrintErrln(">> [ERROR] Failed to " + verb + " workflow run " + p.Run + " of " + p.Workflow + ": " + clierrors.From(err).Error())
+					failed++
+					continue
+				}
+				p.Result = past
+				printer.Println(">> Workflow run " + p.Run + " of " + p.Workflow + " " + past + ".")
+			}
+			if printer.Structured() {
+				if err := printer.Print(map[string]interface{}{"msg": pending}, output.Approvals); err != nil {
+					return err
+				}
+			}
+			if failed > 0 {
+				return &clierrors.Error{
+					Category: clierrors.General,
+					Message:  fmt.Sprintf("failed to %s %d of %d workflow runs", verb, failed, len(selected)),
+					Details:  pending,
+				}
+			}
+			return nil
+		},
+	}
+
+	approvalCmd.Flags().StringVar(&opts.WfId, "workflow-id", "", "The workflow ID of the run. With --pending, only the runs of this workflow are listed.")
+	approvalCmd.Flags().StringVar(&opts.Comment, "comment", "", "A comment recorded with the decision, e.g. the reason for a rejection.")
+	approvalCmd.Flags().BoolVar(&opts.Pending, "pending", false, "List every run waiting for an approval in the workflow group and "+verb+" the selected ones.")
+	approvalCmd.Flags().StringVar(&opts.StackId, "stack-id", "", "The stack of the runs, for runs of stack workflows.")
+	approvalCmd.Flags().StringVar(&opts.StackRun, "stack-run", "", "List the runs waiting for an approval in this stack run instead of the workflow group. Requires --stack-id.")
+	approvalCmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "Do not ask for a confirmation, "+verb+" all pending runs.")
+
+	return approvalCmd
+}
+
+// findPending returns the runs waiting for an approval in the stack run or the workflow group
+func findPending(ctx context.Context, c *client.Client, opts *RunOptions) ([]*Pending, error) {
+	pending := []*Pending{}
+	if opts.StackRun != "" {
+		response, err := c.StackRuns.ReadStackRun(ctx, opts.Org, opts.StackId, opts.StackRun, opts.WfgGrp)
+		if err != nil {
+			return nil, clierrors.Wrap(err, "Failed to read stack run %s", opts.StackRun)
+		}
+		for _, item := range response.GetMsg().GetWfRuns() {
+			run, _ := item.(map[string]interface{})
+			p := &Pending{Workflow: runs.WorkflowOf(run), Run: output.Cell(run["ResourceName"]), Stack: opts.StackId, Status: output.Cell(run["LatestStatus"])}
+			if p.Status == runs.ApprovalRequired && (opts.WfId == "" || opts.WfId == p.Workflow) {
+				pending = append(pending, p)
+			}
+		}
+		return pending, nil
+	}
+
+	request := &sggosdk.ListAllWorkflowsRequest{}
+	for {
+		page, err := c.Workflows.ListAllWorkflows(ctx, opts.Org, opts.WfgGrp, request)
+		if err != nil {
+			return nil, clierrors.Wrap(err, "Failed to list workflows")
+		}
+		for _, workflow := range page.Msg {
+			if workflow.LatestWfrunStatus != runs.ApprovalRequired || (opts.WfId != "" && opts.WfId != workflow.ResourceName) {
+				continue
+			}
+			workflowRuns, err := c.WorkflowRuns.ListAllWorkflowRuns(ctx, opts.Org, workflow.ResourceName, opts.WfgGrp, &sggosdk.ListAllWorkflowRunsRequest{})
+			if err != nil {
+				return nil, clierrors.Wrap(err, "Failed to list the runs of workflow %s", workflow.ResourceName)
+			}
+			for _, run := range workflowRuns.Msg {
+				if run.LatestStatus == runs.ApprovalRequired {
+					pending = append(pending, &Pending{Workflow: workflow.ResourceName, Run: run.ResourceName, Status: run.LatestStatus})
+				}
+			}
+		}
+		if page.Lastevaluatedkey == "" {
+			return pending, nil
+		}
+		request.Lastevaluatedkey = sggosdk.String(page.Lastevaluatedkey)
+	}
+}
+
+// planSummary returns the plan summary from the logs of a run
+func planSummary(ctx context.Context, c *client.Client, opts *RunOptions, p *Pending) (string, error) {
+	var response *sggosdk.GeneratedWorkflowRunLogs
+	var err error
+	if p.Stack != "" {
+		response, err = c.StackWorkflowRuns.ReadStackWorkflowRunLogs(ctx, opts.Org, p.Stack, p.Workflow, opts.WfgGrp, p.Run)
+	} else {
+		response, err = c.WorkflowRuns.ReadWorkflowRunLogs(ctx, opts.Org, p.Workflow, opts.WfgGrp, p.Run)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "the server responded with nothing") {
+			return "", nil
+		}
+		return "", err
+	}
+	return logs.PlanSummary(ctx, response)
+}
+
+// resume approves or rejects a run
+func resume(ctx context.Context, c *client.Client, opts *RunOptions, p *Pending, approve bool) error {
+	approval := &sggosdk.WorkflowRunApproval{Approve: sggosdk.Bool(approve)}
+	if opts.Comment != "" {
+		approval.Message = sggosdk.String(opts.Comment)
+	}
+	if p.Stack != "" {
+		return c.StackWorkflowRuns.ApproveStackWorkflowRun(ctx, opts.Org, p.Stack, p.Workflow, opts.WfgGrp, p.Run, approval)
+	}
+	_, err := c.WorkflowRuns.ApproveWorkflowRun(ctx, opts.Org, p.Workflow, opts.WfgGrp, p.Run, approval)
+	return err
+}
+
+// table lists the pending runs with the numbers used to select them
+func table(pending []*Pending) string {
+	b := &bytes.Buffer{}
+	w := tabwriter.NewWriter(b, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "#\tWORKFLOW\tRUN\tPLAN")
+	for i, p := range pending {
+		plan := p.Plan
+		if plan == "" {
+			plan = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", i+1, p.Workflow, p.Run, plan)
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// confirm asks which of the pending runs to approve or reject, all of them with --yes
+func confirm(cmd *cobra.Command, pending []*Pending, verb, title string, yes bool) ([]*Pending, error) {
+	if yes {
+		return pending, nil
+	}
+	in := cmd.InOrStdin()
+	if f, ok := in.(*os.File); ok && !term.IsTerminal(int(f.Fd())) {
+		return nil, clierrors.New(clierrors.Usage, "cannot ask for a confirmation without a terminal, pass --yes to %s all pending runs", verb)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "%s which runs? Enter all, none or their numbers, e.g. 1,3 [none]: ", title)
+	line, _ := bufio.NewReader(in).ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(line))
+	switch answer {
+	case "all", "a", "yes", "y":
+		return pending, nil
+	case "", "none", "n", "no":
+		return nil, nil
+	}
+	var selected []*Pending
+	for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > len(pending) {
+			return nil, clierrors.New(clierrors.Usage, "invalid selection %q, enter all, none or numbers between 1 and %d", field, len(pending))
+		}
+		selected = append(selected, pending[n-1])
+	}
+	return selected, nil
+}
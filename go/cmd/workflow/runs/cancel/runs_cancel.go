@@ -0,0 +1,60 @@
+package cancel
+
+import (
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/wait"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-cli/runs"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+)
+
+type RunOptions struct {
+	Org    string
+	WfgGrp string
+	WfId   string
+	Wait   bool
+	wait.Options
+}
+
+func NewCancelCmd(c *client.Client) *cobra.Command {
+	opts := &RunOptions{}
+	// cancelCmd represents the cancel command
+	var cancelCmd = &cobra.Command{
+		Use:   "cancel <run-id>",
+		Short: "Cancel a queued or running workflow run",
+		Long: `Cancel a queued or running workflow run.
+With --wait, the command returns once the run stopped and exits with 0 when it was cancelled.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
+			opts.Org = cmd.Flags().Lookup("org").Value.String()
+			opts.WfgGrp = cmd.Flags().Lookup("workflow-group").Value.String()
+			response, err := c.WorkflowRuns.CancelWorkflowRun(cmd.Context(), opts.Org, opts.WfId, opts.WfgGrp, args[0])
+			if err != nil {
+				return clierrors.Wrap(err, "Failed to cancel workflow run %s", args[0])
+			}
+			if printer.Structured() && !opts.Wait {
+				if err := printer.Print(response, output.Message); err != nil {
+					return err
+				}
+			}
+			printer.Println("Workflow run " + args[0] + " cancelled.")
+			if opts.Wait {
+				opts.Until = append(opts.Until, runs.Cancelled)
+				return wait.ForRun(cmd.Context(), c, printer, opts.Org, opts.WfgGrp, opts.WfId, args[0], &opts.Options)
+			}
+			return nil
+		},
+	}
+
+	cancelCmd.Flags().StringVar(&opts.WfId, "workflow-id", "", "The workflow ID of the run.")
+	cancelCmd.MarkFlagRequired("workflow-id")
+	cancelCmd.Flags().BoolVar(&opts.Wait, "wait", false, "Wait until the run stopped. The exit code reflects its final status.")
+	wait.AddFlags(cancelCmd, &opts.Options)
+
+	return cancelCmd
+}
@@ -0,0 +1,123 @@
+package list
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-cli/runs"
+	sggosdk "github.com/StackGuardian/sg-sdk-go"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+)
+
+type RunOptions struct {
+	Org    string
+	WfgGrp string
+	WfId   string
+	Status []string
+	Since  string
+	Until  string
+	Limit  int
+	All    bool
+}
+
+func NewListCmd(c *client.Client) *cobra.Command {
+	opts := &RunOptions{}
+	// listCmd represents the list command
+	var listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the runs of a workflow",
+		Long: `List the runs of a workflow with their action, status, initiator, start time, duration and VCS commit.
+All pages are fetched until --limit runs matched the filters.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
+			opts.Org = cmd.Flags().Lookup("org").Value.String()
+			opts.WfgGrp = cmd.Flags().Lookup("workflow-group").Value.String()
+			opts.WfId = cmd.Flags().Lookup("workflow-id").Value.String()
+
+			now := time.Now()
+			since, err := runs.ParseTime(opts.Since, now)
+			if err != nil {
+				return clierrors.New(clierrors.Usage, "invalid --since: %s", err)
+			}
+			until, err := runs.ParseTime(opts.Until, now)
+			if err != nil {
+				return clierrors.New(clierrors.Usage, "invalid --until: %s", err)
+			}
+			if opts.Limit < 1 && !opts.All {
+				return clierrors.New(clierrors.Usage, "--limit must be at least 1, use --all to list all runs")
+			}
+
+			matched := []json.RawMessage{}
+			truncated := false
+			request := &sggosdk.ListAllWorkflowRunsRequest{}
+			for !truncated {
+				page, err := c.WorkflowRuns.ListAllWorkflowRuns(cmd.Context(), opts.Org, opts.WfId, opts.WfgGrp, request)
+				if err != nil {
+					return clierrors.Wrap(err, "Failed to list the runs of workflow %s", opts.WfId)
+				}
+				for _, run := range page.Msg {
+					if !opts.All && len(matched) == opts.Limit {
+						truncated = true
+						break
+					}
+					if matches(run, opts.Status, since, until) {
+						matched = append(matched, json.RawMessage(run.String()))
+					}
+				}
+				if page.Lastevaluatedkey == "" {
+					break
+				}
+				if !opts.All && len(matched) == opts.Limit {
+					truncated = true
+				}
+				request.Lastevaluatedkey = sggosdk.String(page.Lastevaluatedkey)
+			}
+
+			if err := printer.PrintDefault(map[string]interface{}{"msg": matched}, output.WorkflowRuns, "table"); err != nil {
+				return err
+			}
+			if truncated {
+				cmd.PrintErrf(">> Showing the first %d matching runs, use --limit or --all to list more.\n", opts.Limit)
+			}
+			return nil
+		},
+	}
+
+	listCmd.Flags().StringSliceVar(&opts.Status, "status", nil, "Only list runs with one of these statuses, e.g. ERRORED,CANCELLED.")
+	listCmd.Flags().StringVar(&opts.Since, "since", "", "Only list runs started at or after this time: a date (2024-10-25), an RFC 3339 time or a duration ago (24h, 7d).")
+	listCmd.Flags().StringVar(&opts.Until, "until", "", "Only list runs started before this time, in the same formats as --since.")
+	listCmd.Flags().IntVar(&opts.Limit, "limit", 20, "Maximum number of runs to list.")
+	listCmd.Flags().BoolVar(&opts.All, "all", false, "List all matching runs, ignoring --limit.")
+	listCmd.Flags().String("workflow-id", "", "The workflow ID of the runs.")
+	listCmd.MarkFlagRequired("workflow-id")
+
+	return listCmd
+}
+
+// matches reports whether a run passes the status and date filters
+func matches(run *sggosdk.GeneratedWorkflowRunListAllMsg, statuses []string, since, until time.Time) bool {
+	if len(statuses) > 0 {
+		found := false
+		for _, status := range statuses {
+			found = found || strings.EqualFold(status, run.LatestStatus)
+		}
+		if !found {
+			return false
+		}
+	}
+	started := time.UnixMilli(int64(run.CreatedAt))
+	if !since.IsZero() && started.Before(since) {
+		return false
+	}
+	if !until.IsZero() && !started.Before(until) {
+		return false
+	}
+	return true
+}
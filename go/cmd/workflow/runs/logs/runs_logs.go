@@ -0,0 +1,215 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-cli/runs"
+	"github.com/StackGuardian/sg-cli/transport"
+	sggosdk "github.com/StackGuardian/sg-sdk-go"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+)
+
+// Options are the flags of workflow runs logs, the --logs flag of workflow apply follows the run with the defaults
+type Options struct {
+	Follow   bool
+	Step     string
+	Since    string
+	Interval time.Duration
+}
+
+type RunOptions struct {
+	Options
+	Org    string
+	WfgGrp string
+	WfId   string
+}
+
+func NewLogsCmd(c *client.Client) *cobra.Command {
+	opts := &RunOptions{}
+	// logsCmd represents the logs command
+	var logsCmd = &cobra.Command{
+		Use:   "logs <run-id>",
+		Short: "Print the logs of a workflow run",
+		Long: `Print the logs of a workflow run step by step, each step starting with a ">> Step <name>" line.
+With --follow, new output is printed until the run finishes and the exit code reflects its final status
+like workflow wait: 0 for COMPLETED, 11 for ERRORED or FAILED and 12 for CANCELLED or REJECTED.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
+			if printer.Structured() {
+				return clierrors.New(clierrors.Usage, "workflow runs logs prints plain text and does not support --output")
+			}
+			opts.Org = cmd.Flags().Lookup("org").Value.String()
+			opts.WfgGrp = cmd.Flags().Lookup("workflow-group").Value.String()
+			opts.WfId = cmd.Flags().Lookup("workflow-id").Value.String()
+			status, err := Stream(cmd.Context(), c, printer.Text(), opts.Org, opts.WfgGrp, opts.WfId, args[0], &opts.Options)
+			if err != nil || !opts.Follow {
+				return err
+			}
+			return runs.Outcome("Workflow run "+args[0], status, nil)
+		},
+	}
+
+	logsCmd.Flags().BoolVarP(&opts.Follow, "follow", "f", false, "Keep printing new output until the run finishes.")
+	logsCmd.Flags().StringVar(&opts.Step, "step", "", "Only print the logs of this step, e.g. pre_0_step.")
+	logsCmd.Flags().StringVar(&opts.Since, "since", "", "Skip the steps whose last status change is older than this time: a date (2024-10-25), an RFC 3339 time or a duration ago (10m, 1d).")
+	logsCmd.Flags().DurationVar(&opts.Interval, "poll-interval", runs.DefaultInterval, "Time between two checks for new output with --follow.")
+	logsCmd.Flags().String("workflow-id", "", "The workflow ID of the run.")
+	logsCmd.MarkFlagRequired("workflow-id")
+
+	return logsCmd
+}
+
+// Stream prints the logs of a workflow run to w and returns the status of the run.
+// With Follow, it polls for new output until the run finishes.
+func Stream(ctx context.Context, c *client.Client, w io.Writer, org, wfGrp, wf, run string, opts *Options) (string, error) {
+	since, err := runs.ParseTime(opts.Since, time.Now())
+	if err != nil {
+		return "", clierrors.New(clierrors.Usage, "invalid --since: %s", err)
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = runs.DefaultInterval
+	}
+	printed := map[string]int{}
+	current := ""
+	partial := false
+	found := false
+	for {
+		response, err := c.WorkflowRuns.ReadWorkflowRun(ctx, org, wf, wfGrp, run)
+		if err != nil {
+			return "", clierrors.Wrap(err, "Failed to read workflow run %s", run)
+		}
+		status := response.Msg.GetLatestStatus()
+
+		logs, err := c.WorkflowRuns.ReadWorkflowRunLogs(ctx, org, wf, wfGrp, run)
+		if err != nil && !strings.Contains(err.Error(), "the server responded with nothing") {
+			return status, clierrors.Wrap(err, "Failed to read the logs of workflow run %s", run)
+		}
+		for i, entry := range logs.GetMsg() {
+			step := stepName(entry, i)
+			if opts.Step != "" && step != opts.Step {
+				continue
+			}
+			found = true
+			if !since.IsZero() && changedBefore(response.Msg, step, since) {
+				continue
+			}
+			text, err := fetch(ctx, entry.GetLogUrl())
+			if err != nil {
+				return status, clierrors.Wrap(err, "Failed to download the logs of step %s", step)
+			}
+			if len(text) <= printed[step] {
+				continue
+			}
+			if step != current {
+				if partial {
+					fmt.Fprintln(w)
+				}
+				fmt.Fprintf(w, ">> Step %s\n", step)
+				current = step
+			}
+			w.Write(text[printed[step]:])
+			printed[step] = len(text)
+			partial = text[len(text)-1] != '\n'
+		}
+
+		if !opts.Follow || runs.Terminal(status) {
+			if partial {
+				fmt.Fprintln(w)
+			}
+			if opts.Step != "" && !found {
+				return status, clierrors.New(clierrors.NotFound, "workflow run %s has no logs for step %s", run, opts.Step)
+			}
+			return status, nil
+		}
+		if err := runs.Sleep(ctx, opts.Interval); err != nil {
+			return status, clierrors.Wrap(err, "Stopped following the logs of workflow run %s", run)
+		}
+	}
+}
+
+// stepName returns the name of the step a log belongs to, from the log entry or the file name of its URL
+func stepName(entry *sggosdk.GeneratedWorkflowRunLogsMsg, index int) string {
+	for _, key := range []string{"stepName", "step", "name"} {
+		if name, ok := entry.GetExtraProperties()[key].(string); ok && name != "" {
+			return name
+		}
+	}
+	if u, err := url.Parse(entry.GetLogUrl()); err == nil {
+		if name, _, _ := strings.Cut(path.Base(u.Path), "."); name != "" && name != "/" {
+			return name
+		}
+	}
+	return fmt.Sprintf("step_%d", index)
+}
+
+// changedBefore reports whether the last status change of a step is older than since.
+// Steps without a known status are never skipped.
+func changedBefore(run *sggosdk.GeneratedWorkflowRunsGetMsg, step string, since time.Time) bool {
+	statuses := run.GetStatuses()[step]
+	if len(statuses) == 0 {
+		return false
+	}
+	last := 0.0
+	for _, status := range statuses {
+		last = max(last, status.GetCreatedAt())
+	}
+	return time.UnixMilli(int64(last)).Before(since)
+}
+
+// fetch downloads a log from its signed URL
+func fetch(ctx context.Context, logURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, logURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := transport.Client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		// The log of a step is only uploaded once the step started
+		return nil, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, clierrors.New(clierrors.Server, "expected status code 200, got %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// planSummary matches the summary line of a Terraform plan
+var planSummary = regexp.MustCompile(`Plan: \d+ to add, \d+ to change, \d+ to destroy\.|No changes\.`)
+
+// ansiColor matches the color codes of the Terraform output
+var ansiColor = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// PlanSummary returns the last plan summary found in the logs, e.g. "Plan: 1 to add, 0 to change, 0 to destroy.",
+// or an empty string if the logs have none
+func PlanSummary(ctx context.Context, logs *sggosdk.GeneratedWorkflowRunLogs) (string, error) {
+	summary := ""
+	for _, entry := range logs.GetMsg() {
+		text, err := fetch(ctx, entry.GetLogUrl())
+		if err != nil {
+			return "", err
+		}
+		if matches := planSummary.FindAll(ansiColor.ReplaceAll(text, nil), -1); len(matches) > 0 {
+			summary = string(matches[len(matches)-1])
+		}
+	}
+	return summary, nil
+}
@@ -0,0 +1,171 @@
+package read
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+)
+
+// inputs are the fields of a run that hold the configuration it was resolved with
+var inputs = []string{"RuntimeParameters", "TerraformAction", "TerraformConfig", "EnvironmentVariables", "VCSConfig",
+	"DeploymentPlatformConfig", "WfStepsConfig", "RunnerConstraints", "UserJobCPU", "UserJobMemory"}
+
+type RunOptions struct {
+	Org    string
+	WfgGrp string
+	WfId   string
+	Run    string
+}
+
+func NewReadCmd(c *client.Client) *cobra.Command {
+	opts := &RunOptions{}
+	// readCmd represents the read command
+	var readCmd = &cobra.Command{
+		Use:   "read <run-id>",
+		Short: "Get details of a workflow run",
+		Long: `Get details of a workflow run: its resolved inputs, the statuses of its steps and its artifacts.
+With --output, the run is printed with the additional keys "steps", "inputs" and "artifacts" next to "msg".`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
+			opts.Org = cmd.Flags().Lookup("org").Value.String()
+			opts.WfgGrp = cmd.Flags().Lookup("workflow-group").Value.String()
+			opts.WfId = cmd.Flags().Lookup("workflow-id").Value.String()
+			opts.Run = args[0]
+
+			response, err := c.WorkflowRuns.ReadWorkflowRun(cmd.Context(), opts.Org, opts.WfId, opts.WfgGrp, opts.Run)
+			if err != nil {
+				return clierrors.Wrap(err, "Failed to read workflow run %s", opts.Run)
+			}
+			var run map[string]interface{}
+			decoder := json.NewDecoder(strings.NewReader(response.Msg.String()))
+			decoder.UseNumber()
+			if err := decoder.Decode(&run); err != nil {
+				return clierrors.Wrap(err, "Failed to decode workflow run %s", opts.Run)
+			}
+
+			artifacts := map[string]interface{}{}
+			list, err := c.Workflows.ListAllWorkflowArtifacts(cmd.Context(), opts.Org, opts.WfId, opts.WfgGrp)
+			if err != nil && !strings.Contains(err.Error(), "the server responded with nothing") {
+				return clierrors.Wrap(err, "Failed to list the artifacts of workflow run %s", opts.Run)
+			}
+			if list != nil && list.Data != nil {
+				for name, artifact := range list.Data.Artifacts {
+					if strings.Contains(name, "/wfruns/"+opts.Run+"/") {
+						artifacts[name] = artifact
+					}
+				}
+			}
+
+			doc := map[string]interface{}{
+				"msg":       run,
+				"steps":     steps(run),
+				"inputs":    resolvedInputs(run),
+				"artifacts": artifacts,
+			}
+			if printer.Structured() {
+				return printer.Print(doc, output.WorkflowRuns)
+			}
+			printer.Println(summary(doc))
+			return nil
+		},
+	}
+
+	readCmd.Flags().String("workflow-id", "", "The workflow ID of the run.")
+	readCmd.MarkFlagRequired("workflow-id")
+
+	return readCmd
+}
+
+// steps flattens the statuses of the steps of a run in the order they happened
+func steps(run map[string]interface{}) []map[string]interface{} {
+	steps := []map[string]interface{}{}
+	statuses, _ := run["Statuses"].(map[string]interface{})
+	for step, history := range statuses {
+		entries, _ := history.([]interface{})
+		for _, entry := range entries {
+			status, _ := entry.(map[string]interface{})
+			steps = append(steps, map[string]interface{}{"step": step, "status": status["name"], "at": status["createdAt"]})
+		}
+	}
+	sort.SliceStable(steps, func(i, j int) bool {
+		a, b := millis(steps[i]["at"]), millis(steps[j]["at"])
+		if a == b {
+			return output.Cell(steps[i]["step"]) < output.Cell(steps[j]["step"])
+		}
+		return a < b
+	})
+	return steps
+}
+
+func millis(value interface{}) float64 {
+	n, _ := value.(json.Number)
+	f, _ := n.Float64()
+	return f
+}
+
+// resolvedInputs returns the configuration fields present in a run
+func resolvedInputs(run map[string]interface{}) map[string]interface{} {
+	resolved := map[string]interface{}{}
+	for _, key := range inputs {
+		if value, ok := run[key]; ok && value != nil {
+			resolved[key] = value
+		}
+	}
+	return resolved
+}
+
+// summary renders a run in human readable form
+func summary(doc map[string]interface{}) string {
+	run := doc["msg"].(map[string]interface{})
+	b := &bytes.Buffer{}
+	w := tabwriter.NewWriter(b, 0, 8, 3, ' ', 0)
+	parameters, _ := run["RuntimeParameters"].(map[string]interface{})
+	action, _ := parameters["terraformAction"].(map[string]interface{})
+	fmt.Fprintf(w, "Run:\t%s\n", output.Cell(run["ResourceName"]))
+	fmt.Fprintf(w, "Action:\t%s\n", output.Cell(action["action"]))
+	fmt.Fprintf(w, "Status:\t%s\n", output.Cell(run["LatestStatus"]))
+	fmt.Fprintf(w, "Initiator:\t%s\n", output.First(run["Authors"]))
+	fmt.Fprintf(w, "Started:\t%s\n", output.Timestamp(run["CreatedAt"]))
+	fmt.Fprintf(w, "Duration:\t%s\n", output.RunDuration(run))
+	fmt.Fprintf(w, "Commit:\t%s\n", output.Commit(run))
+	w.Flush()
+
+	fmt.Fprintln(b, "\nSteps:")
+	w = tabwriter.NewWriter(b, 0, 8, 3, ' ', 0)
+	for _, step := range doc["steps"].([]map[string]interface{}) {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", output.Cell(step["step"]), output.Cell(step["status"]), output.Timestamp(step["at"]))
+	}
+	w.Flush()
+
+	fmt.Fprintln(b, "\nInputs:")
+	for _, key := range inputs {
+		if value, ok := doc["inputs"].(map[string]interface{})[key]; ok {
+			indented, _ := json.MarshalIndent(value, "  ", "  ")
+			fmt.Fprintf(b, "  %s: %s\n", key, indented)
+		}
+	}
+
+	fmt.Fprintln(b, "\nArtifacts:")
+	artifacts := doc["artifacts"].(map[string]interface{})
+	names := make([]string, 0, len(artifacts))
+	for name := range artifacts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(b, "  %s\n", name)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
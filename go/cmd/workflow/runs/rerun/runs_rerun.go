@@ -0,0 +1,143 @@
+package rerun
+
+import (
+	"encoding/json"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/wait"
+	"github.com/StackGuardian/sg-cli/dashboard"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-cli/utilities"
+	sggosdk "github.com/StackGuardian/sg-sdk-go"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+)
+
+// inputs are the fields of a new run copied from the original run, with the key of each one in its RuntimeParameters
+var inputs = []struct{ field, parameter string }{
+	{"TerraformAction", "terraformAction"},
+	{"TerraformConfig", "terraformConfig"},
+	{"EnvironmentVariables", "environmentVariables"},
+	{"VCSConfig", "vcsConfig"},
+	{"DeploymentPlatformConfig", "deploymentPlatformConfig"},
+	{"WfStepsConfig", "wfStepsConfig"},
+	{"WfType", "wfType"},
+	{"RunnerConstraints", "runnerConstraints"},
+	{"UserJobCPU", "userJobCPU"},
+	{"UserJobMemory", "userJobMemory"},
+	{"MiniSteps", "miniSteps"},
+}
+
+type RunOptions struct {
+	Org          string
+	WfgGrp       string
+	WfId         string
+	Action       string
+	PatchPayload string
+	Wait         bool
+	wait.Options
+}
+
+func NewRerunCmd(c *client.Client) *cobra.Command {
+	opts := &RunOptions{}
+	// rerunCmd represents the rerun command
+	var rerunCmd = &cobra.Command{
+		Use:   "rerun <run-id>",
+		Short: "Start a new run with the inputs of a workflow run",
+		Long: `Start a new run of the workflow with the action and the resolved inputs of an earlier run,
+e.g. to retry a run that failed on a flaky provider. --action and --patch-payload override them.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
+			opts.Org = cmd.Flags().Lookup("org").Value.String()
+			opts.WfgGrp = cmd.Flags().Lookup("workflow-group").Value.String()
+			if opts.Action != "" {
+				if _, err := sggosdk.NewActionEnumFromString(opts.Action); err != nil {
+					return clierrors.New(clierrors.Usage, "invalid --action %q, valid actions are apply, destroy, plan, plan-destroy, plan-without-policy and refresh", opts.Action)
+				}
+			}
+			if opts.PatchPayload != "" && !utilities.IsObject(opts.PatchPayload) {
+				return clierrors.New(clierrors.Validation, "--patch-payload requires a JSON object")
+			}
+
+			original, err := c.WorkflowRuns.ReadWorkflowRun(cmd.Context(), opts.Org, opts.WfId, opts.WfgGrp, args[0])
+			if err != nil {
+				return clierrors.Wrap(err, "Failed to read workflow run %s", args[0])
+			}
+			request, err := rerunRequest(original.Msg.String(), opts)
+			if err != nil {
+				return err
+			}
+			response, err := c.WorkflowRuns.CreateWorkflowRun(cmd.Context(), opts.Org, opts.WfId, opts.WfgGrp, request)
+			if err != nil {
+				return clierrors.Wrap(err, "Failed to rerun workflow run %s", args[0])
+			}
+			if printer.Structured() && !opts.Wait {
+				if err := printer.Print(response, output.WorkflowRun); err != nil {
+					return err
+				}
+			}
+			run, err := wait.RunID(response)
+			if err != nil {
+				return err
+			}
+			printer.Println("Workflow run " + args[0] + " rerun as " + run + ".")
+			printer.Println("To view the workflow run, please visit the following URL:")
+			printer.Println(dashboard.Default().WorkflowRun(opts.Org, opts.WfgGrp, opts.WfId, run))
+			if opts.Wait {
+				return wait.ForRun(cmd.Context(), c, printer, opts.Org, opts.WfgGrp, opts.WfId, run, &opts.Options)
+			}
+			return nil
+		},
+	}
+
+	rerunCmd.Flags().StringVar(&opts.WfId, "workflow-id", "", "The workflow ID of the run.")
+	rerunCmd.MarkFlagRequired("workflow-id")
+	rerunCmd.Flags().StringVar(&opts.Action, "action", "", "Run this Terraform action instead of the one of the original run, e.g. plan.")
+	rerunCmd.Flags().StringVar(&opts.PatchPayload, "patch-payload", "", "Patch the inputs of the original run. Add or replace values. Requires valid JSON input.")
+	rerunCmd.Flags().BoolVar(&opts.Wait, "wait", false, "Wait for the new run to finish. The exit code reflects its final status.")
+	wait.AddFlags(rerunCmd, &opts.Options)
+
+	return rerunCmd
+}
+
+// rerunRequest builds a workflow run with the inputs of the original run and the overrides
+func rerunRequest(original string, opts *RunOptions) (*sggosdk.WorkflowRun, error) {
+	var run map[string]interface{}
+	if err := json.Unmarshal([]byte(original), &run); err != nil {
+		return nil, clierrors.Wrap(err, "Failed to decode the original workflow run")
+	}
+	parameters, _ := run["RuntimeParameters"].(map[string]interface{})
+	payload := map[string]interface{}{}
+	for _, input := range inputs {
+		if value, ok := parameters[input.parameter]; ok && value != nil {
+			payload[input.field] = value
+		} else if value, ok := run[input.field]; ok && value != nil {
+			payload[input.field] = value
+		}
+	}
+	if opts.Action != "" {
+		action, _ := payload["TerraformAction"].(map[string]interface{})
+		if action == nil {
+			action = map[string]interface{}{}
+		}
+		action["action"] = opts.Action
+		payload["TerraformAction"] = action
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, clierrors.Wrap(err, "Failed to encode the workflow run")
+	}
+	if opts.PatchPayload != "" {
+		data = []byte(utilities.PatchJSON(string(data), opts.PatchPayload))
+	}
+	var request *sggosdk.WorkflowRun
+	if err := json.Unmarshal(data, &request); err != nil {
+		return nil, &clierrors.Error{Category: clierrors.Validation, Message: "Error while unmarshalling WorkflowRun payload", Err: err}
+	}
+	return request, nil
+}
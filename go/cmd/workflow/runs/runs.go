@@ -0,0 +1,43 @@
+package runs
+
+import (
+	"fmt"
+
+	"github.com/StackGuardian/sg-cli/cmd/workflow/runs/approval"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/runs/cancel"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/runs/list"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/runs/logs"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/runs/read"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/runs/rerun"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+)
+
+func NewRunsCmd(c *client.Client) *cobra.Command {
+	// runsCmd represents the runs command
+	var runsCmd = &cobra.Command{
+		Use:   "runs",
+		Short: "Inspect the runs of a workflow",
+		Long:  `Inspect the run history of a workflow.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Println(`Sub-commands:
+  list        List the runs of a workflow
+  read        Get details of a workflow run
+  logs        Print the logs of a workflow run
+  approve     Approve workflow runs waiting for an approval
+  reject      Reject workflow runs waiting for an approval
+  cancel      Cancel a queued or running workflow run
+  rerun       Start a new run with the inputs of a workflow run`)
+		},
+	}
+
+	runsCmd.AddCommand(list.NewListCmd(c))
+	runsCmd.AddCommand(read.NewReadCmd(c))
+	runsCmd.AddCommand(logs.NewLogsCmd(c))
+	runsCmd.AddCommand(approval.NewApproveCmd(c))
+	runsCmd.AddCommand(approval.NewRejectCmd(c))
+	runsCmd.AddCommand(cancel.NewCancelCmd(c))
+	runsCmd.AddCommand(rerun.NewRerunCmd(c))
+
+	return runsCmd
+}
@@ -0,0 +1,111 @@
+package tfstate
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/StackGuardian/sg-cli/transport"
+	"github.com/StackGuardian/sg-sdk-go/client"
+)
+
+// Download writes the Terraform state of a workflow to w. It reports false when the workflow has no state.
+// The state is the tfstate.json artifact of the workflow, as listed by artifacts list.
+func Download(ctx context.Context, c *client.Client, org, wfGrp, wf string, w io.Writer) (bool, error) {
+	list, err := c.Workflows.ListAllWorkflowArtifacts(ctx, org, wf, wfGrp)
+	if err != nil {
+		// The API answers with nothing when the workflow has no artifacts
+		if clierrors.From(err).Category == clierrors.NotFound {
+			return false, nil
+		}
+		return false, clierrors.Wrap(err, "Failed to list the artifacts of workflow %s", wf)
+	}
+	artifactURL := ""
+	for name, artifact := range list.GetData().GetArtifacts() {
+		if strings.HasSuffix(name, "/wfs/"+wf+"/artifacts/tfstate.json") {
+			artifactURL = artifact.GetUrl()
+		}
+	}
+	if artifactURL == "" {
+		return false, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, artifactURL, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := transport.Client().Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode != http.StatusOK:
+		return false, clierrors.New(clierrors.Server, "expected status code 200, got %s", resp.Status)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err == nil, err
+}
+
+// Upload replaces the Terraform state of a workflow with the size bytes of state
+func Upload(ctx context.Context, org, wfGrp, wf string, state io.Reader, size int64) error {
+	signed, found, err := signedURL(ctx, org, wfGrp, wf, "tfstate_upload_url")
+	if err != nil {
+		return err
+	}
+	if !found {
+		return clierrors.New(clierrors.NotFound, "workflow %s has no tfstate upload url", wf)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, signed, state)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Accept", "application/json, text/plain, */*")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := transport.Client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return clierrors.New(clierrors.Server, "expected status code 200, got %s", resp.Status)
+	}
+	return nil
+}
+
+// signedURL returns the signed URL of the state of a workflow from endpoint, false when the API answers with 404
+func signedURL(ctx context.Context, org, wfGrp, wf, endpoint string) (string, bool, error) {
+	settings := config.Current()
+	apiURL := settings.APIURL + "/api/v1/orgs/" + url.PathEscape(org) + "/wfgrps/" + url.PathEscape(wfGrp) +
+		"/wfs/" + url.PathEscape(wf) + "/" + endpoint
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return "", false, err
+	}
+	req.Header.Set("Authorization", "apikey "+settings.APIToken)
+	resp, err := transport.Client().Do(req)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", false, nil
+	case resp.StatusCode != http.StatusOK:
+		return "", false, clierrors.New(clierrors.Server, "failed to get %s, got %s", endpoint, resp.Status)
+	}
+	var response struct {
+		Msg string `json:"msg"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", false, err
+	}
+	return response.Msg, response.Msg != "", nil
+}
@@ -0,0 +1,155 @@
+package update
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/jsondiff"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-cli/utilities"
+	sggosdk "github.com/StackGuardian/sg-sdk-go"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+)
+
+type RunOptions struct {
+	Org          string
+	WfgGrp       string
+	WfId         string
+	Preview      bool
+	DryRun       bool
+	PatchPayload string
+	Payload      string
+}
+
+func NewUpdateCmd(c *client.Client) *cobra.Command {
+	opts := &RunOptions{}
+	// updateCmd represents the update command
+	var updateCmd = &cobra.Command{
+		Use:   "update <payload.json>",
+		Short: "Update an existing workflow",
+		Long: `Update an existing workflow with the fields of the payload. Fields that are not in the payload keep their value.
+The changes to the current workflow are printed field by field before the update is sent.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
+			opts.Org = cmd.Parent().PersistentFlags().Lookup("org").Value.String()
+			opts.WfgGrp = cmd.Parent().PersistentFlags().Lookup("workflow-group").Value.String()
+			opts.Payload = args[0]
+
+			payload, err := os.ReadFile(opts.Payload)
+			if err != nil {
+				return clierrors.Wrap(err, "Failed to read workflow payload")
+			}
+			if opts.PatchPayload != "" {
+				if !utilities.IsObject(string(payload)) {
+					return clierrors.New(clierrors.Validation, "Please provide a valid JSON object in %s", opts.Payload)
+				}
+				if !utilities.IsObject(opts.PatchPayload) {
+					return clierrors.New(clierrors.Validation, "--patch-payload requires a JSON object")
+				}
+				payload = []byte(utilities.PatchJSON(string(payload), opts.PatchPayload))
+			}
+			request, err := Decode(payload)
+			if err != nil {
+				return err
+			}
+
+			response, err := c.Workflows.ReadWorkflow(cmd.Context(), opts.Org, opts.WfId, opts.WfgGrp)
+			if err != nil {
+				return clierrors.Wrap(err, "Failed to read workflow %s", opts.WfId)
+			}
+			changes, err := Changes(response.Msg.String(), request)
+			if err != nil {
+				return clierrors.Wrap(err, "Failed to compare workflow %s", opts.WfId)
+			}
+			if len(changes) == 0 {
+				printer.Println(">> Workflow " + opts.WfId + " is up to date, nothing to update.")
+				return nil
+			}
+			printer.Println(">> Changes to workflow " + opts.WfId + ":")
+			printer.Println(jsondiff.Render(changes))
+
+			if opts.DryRun || opts.Preview {
+				requestJson, err := json.MarshalIndent(request, "", "    ")
+				if err != nil {
+					return err
+				}
+				printer.Println(string(requestJson))
+			}
+			if opts.DryRun {
+				return nil
+			}
+
+			updated, err := c.Workflows.UpdateWorkflow(cmd.Context(), opts.Org, opts.WfId, opts.WfgGrp, request)
+			if err != nil {
+				return clierrors.Wrap(err, "Failed to update workflow %s", opts.WfId)
+			}
+			if printer.Structured() {
+				if err := printer.Print(updated, output.Created); err != nil {
+					return err
+				}
+			}
+			printer.Println("Workflow updated successfully.")
+			return nil
+		},
+	}
+
+	updateCmd.Flags().StringVar(&opts.WfId, "workflow-id", "", "The workflow ID to update.")
+	updateCmd.MarkFlagRequired("workflow-id")
+	updateCmd.Flags().StringVar(&opts.PatchPayload, "patch-payload", "", "Patch original payload.json input. Add or replace values. Requires valid JSON input.")
+	updateCmd.Flags().BoolVar(&opts.Preview, "preview", false, "Preview payload content before applying. Execution will not pause.")
+	updateCmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Similar to --preview. But execution will stop, nothing will be applied.")
+
+	return updateCmd
+}
+
+// Decode validates a workflow update payload against the SDK type
+func Decode(payload []byte) (*sggosdk.PatchedWorkflow, error) {
+	var request *sggosdk.PatchedWorkflow
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return nil, &clierrors.Error{Category: clierrors.Validation, Message: "Error while unmarshalling Workflow payload", Err: err}
+	}
+	if request == nil {
+		return nil, clierrors.New(clierrors.Validation, "Please provide a valid JSON payload. The workflow payload should be an object")
+	}
+	if unknown := request.GetExtraProperties(); len(unknown) > 0 {
+		fields := make([]string, 0, len(unknown))
+		for field := range unknown {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		return nil, clierrors.New(clierrors.Validation, "unknown fields in the workflow payload: %s", strings.Join(fields, ", "))
+	}
+	return request, nil
+}
+
+// Changes compares the fields set in an update with the current workflow, the values of secrets are redacted.
+// A field of the update replaces the current one, the fields it does not set are not compared.
+func Changes(current string, request *sggosdk.PatchedWorkflow) ([]jsondiff.Change, error) {
+	var workflow map[string]interface{}
+	if err := json.Unmarshal([]byte(current), &workflow); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	before := map[string]interface{}{}
+	for field := range fields {
+		if value, ok := workflow[field]; ok {
+			before[field] = value
+		}
+	}
+	return jsondiff.Mask(jsondiff.CompareBy(before, fields, jsondiff.Identities...)), nil
+}
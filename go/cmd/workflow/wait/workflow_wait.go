@@ -0,0 +1,99 @@
+package wait
+
+import (
+	"context"
+	"time"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/dashboard"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-cli/runs"
+	sggosdk "github.com/StackGuardian/sg-sdk-go"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+)
+
+// Options are the flags shared by workflow wait and the --wait flag of the commands that start a run
+type Options struct {
+	Interval time.Duration
+	Until    []string
+}
+
+type RunOptions struct {
+	Options
+	Org    string
+	WfgGrp string
+	WfId   string
+}
+
+func NewWaitCmd(c *client.Client) *cobra.Command {
+	opts := &RunOptions{}
+	// waitCmd represents the wait command
+	var waitCmd = &cobra.Command{
+		Use:   "wait <run-id>",
+		Short: "Wait for a workflow run to finish",
+		Long: `Wait for a workflow run to finish and print its status transitions.
+The exit code reflects the final status: 0 for COMPLETED or a status given with --until,
+11 for ERRORED or FAILED and 12 for CANCELLED or REJECTED. The global --timeout stops the wait with 124.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.New(cmd)
+			if err != nil {
+				return err
+			}
+			opts.Org = cmd.Parent().PersistentFlags().Lookup("org").Value.String()
+			opts.WfgGrp = cmd.Parent().PersistentFlags().Lookup("workflow-group").Value.String()
+			return ForRun(cmd.Context(), c, printer, opts.Org, opts.WfgGrp, opts.WfId, args[0], &opts.Options)
+		},
+	}
+
+	waitCmd.Flags().StringVar(&opts.WfId, "workflow-id", "", "The workflow ID of the run.")
+	waitCmd.MarkFlagRequired("workflow-id")
+	AddFlags(waitCmd, &opts.Options)
+
+	return waitCmd
+}
+
+// AddFlags adds --poll-interval and --until to cmd
+func AddFlags(cmd *cobra.Command, opts *Options) {
+	cmd.Flags().DurationVar(&opts.Interval, "poll-interval", runs.DefaultInterval, "Time between two status checks of the run.")
+	cmd.Flags().StringSliceVar(&opts.Until, "until", nil, "Stop waiting once the run reaches one of these statuses, e.g. APPROVAL_REQUIRED. Terminal statuses always stop the wait.")
+}
+
+// RunID returns the ID of a created workflow run
+func RunID(response *sggosdk.WorkflowRunCreatePatchResponse) (string, error) {
+	if response == nil || response.Data == nil || response.Data.ResourceName == "" {
+		return "", clierrors.New(clierrors.General, "the API did not return the ID of the workflow run, cannot wait for it")
+	}
+	return response.Data.ResourceName, nil
+}
+
+// ForRun waits for a workflow run to finish and returns the error for its outcome.
+// With --output, the final state of the run is printed instead of the status transitions only.
+func ForRun(ctx context.Context, c *client.Client, printer *output.Printer, org, wfGrp, wf, run string, opts *Options) error {
+	name := "Workflow run " + run
+	var last *sggosdk.GeneratedWorkflowRunsGet
+	status, err := runs.Watch(ctx, func(ctx context.Context) (string, error) {
+		response, err := c.WorkflowRuns.ReadWorkflowRun(ctx, org, wf, wfGrp, run)
+		if err != nil {
+			return "", clierrors.Wrap(err, "Failed to read workflow run %s", run)
+		}
+		last = response
+		return response.Msg.GetLatestStatus(), nil
+	}, runs.WatchOptions{Name: name, Interval: opts.Interval, Until: opts.Until, Log: printer.Println})
+	if err != nil {
+		if ctx.Err() != nil {
+			printer.Println(">> " + name + " is still " + status + ": " + dashboard.Default().WorkflowRun(org, wfGrp, wf, run))
+			return clierrors.Wrap(err, "Stopped waiting for workflow run %s", run)
+		}
+		return err
+	}
+	if printer.Structured() {
+		if err := printer.Print(last, output.WorkflowRunStatus); err != nil {
+			return err
+		}
+	}
+	printer.Println("To view the workflow run, please visit the following URL:")
+	printer.Println(dashboard.Default().WorkflowRun(org, wfGrp, wf, run))
+	return runs.Outcome(name, status, opts.Until)
+}
@@ -3,12 +3,20 @@ package workflow
 import (
 	"fmt"
 
+	"github.com/StackGuardian/sg-cli/cmd/cmdutil"
 	"github.com/StackGuardian/sg-cli/cmd/workflow/apply"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/clone"
 	"github.com/StackGuardian/sg-cli/cmd/workflow/create"
 	"github.com/StackGuardian/sg-cli/cmd/workflow/delete"
 	"github.com/StackGuardian/sg-cli/cmd/workflow/destroy"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/diff"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/export"
 	"github.com/StackGuardian/sg-cli/cmd/workflow/list"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/plan"
 	"github.com/StackGuardian/sg-cli/cmd/workflow/read"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/runs"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/update"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/wait"
 	"github.com/StackGuardian/sg-sdk-go/client"
 	"github.com/spf13/cobra"
 )
@@ -16,32 +24,45 @@ import (
 func NewWorkflowCmd(c *client.Client) *cobra.Command {
 	// workflowCmd represents the workflow command
 	var workflowCmd = &cobra.Command{
-		Use:   "workflow",
-		Short: "Manage workflows",
-		Long:  `Manage workflows in Stackguardian platform.`,
+		Use:               "workflow",
+		Short:             "Manage workflows",
+		Long:              `Manage workflows in Stackguardian platform.`,
+		PersistentPreRunE: cmdutil.ResolveContext,
 		Run: func(cmd *cobra.Command, args []string) {
 			fmt.Println(`Sub-commands:
   create      Create new workflow
+  update      Update an existing workflow
   delete      Delete the workflow from workflow group
   apply       Execute "Apply" on existing workflow
   destroy     Execute "Destroy" on existing workflow
+  plan        Plan a workflow and summarize the changes
   read        Read, get details of a workflow
-  list        List workflows`)
+  list        List workflows
+  export      Export workflows as payloads for workflow create
+  clone       Copy a workflow to another workflow group or org
+  diff        Compare a workflow payload with the live workflow
+  wait        Wait for a workflow run to finish
+  runs        Inspect the runs of a workflow`)
 		},
 	}
 
-	workflowCmd.PersistentFlags().String("org", "", "The organization name on Stackguardian platform.")
-	workflowCmd.MarkPersistentFlagRequired("org")
+	workflowCmd.PersistentFlags().String("org", "", "The organization name on Stackguardian platform. Defaults to the current context.")
 
-	workflowCmd.PersistentFlags().String("workflow-group", "", "The workflow group under the organization.")
-	workflowCmd.MarkPersistentFlagRequired("workflow-group")
+	workflowCmd.PersistentFlags().String("workflow-group", "", "The workflow group under the organization. Defaults to the current context.")
 
 	workflowCmd.AddCommand(read.NewReadCmd(c))
 	workflowCmd.AddCommand(delete.NewDeleteCmd(c))
 	workflowCmd.AddCommand(create.NewCreateCmd(c))
+	workflowCmd.AddCommand(update.NewUpdateCmd(c))
 	workflowCmd.AddCommand(apply.NewApplyCmd(c))
 	workflowCmd.AddCommand(list.NewListCmd(c))
+	workflowCmd.AddCommand(export.NewExportCmd(c))
+	workflowCmd.AddCommand(clone.NewCloneCmd(c))
+	workflowCmd.AddCommand(diff.NewDiffCmd(c))
 	workflowCmd.AddCommand(destroy.NewDestroyCmd(c))
+	workflowCmd.AddCommand(plan.NewPlanCmd(c))
+	workflowCmd.AddCommand(wait.NewWaitCmd(c))
+	workflowCmd.AddCommand(runs.NewRunsCmd(c))
 
 	return workflowCmd
 }
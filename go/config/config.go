@@ -0,0 +1,270 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DefaultProfile      = "default"
+	DefaultAPIURL       = "https://api.app.stackguardian.io"
+	DefaultDashboardURL = "https://app.stackguardian.io/orchestrator"
+)
+
+// Profile holds the settings for a single Stackguardian tenant.
+// The yaml tag of every field is also the key used by `sg-cli config get/set`.
+type Profile struct {
+	APIToken      string `yaml:"api-token,omitempty"`
+	APIURL        string `yaml:"api-url,omitempty"`
+	DashboardURL  string `yaml:"dashboard-url,omitempty"`
+	Org           string `yaml:"org,omitempty"`
+	WorkflowGroup string `yaml:"workflow-group,omitempty"`
+	// Connection settings, see the transport package
+	CAFile         string `yaml:"ca-file,omitempty"`
+	ClientCert     string `yaml:"client-cert,omitempty"`
+	ClientKey      string `yaml:"client-key,omitempty"`
+	Proxy          string `yaml:"proxy,omitempty"`
+	RequestTimeout string `yaml:"request-timeout,omitempty"`
+	MaxAttempts    string `yaml:"max-attempts,omitempty"`
+}
+
+// File is the on-disk representation of the sg-cli config file
+type File struct {
+	CurrentProfile string              `yaml:"current-profile,omitempty"`
+	Profiles       map[string]*Profile `yaml:"profiles,omitempty"`
+}
+
+// envOverrides maps config keys to the environment variables that override them
+var envOverrides = map[string]string{
+	"api-token":       "SG_API_TOKEN",
+	"api-url":         "SG_BASE_URL",
+	"dashboard-url":   "SG_DASHBOARD_URL",
+	"org":             "SG_ORG",
+	"workflow-group":  "SG_WORKFLOW_GROUP",
+	"ca-file":         "SG_CA_FILE",
+	"client-cert":     "SG_CLIENT_CERT",
+	"client-key":      "SG_CLIENT_KEY",
+	"proxy":           "SG_PROXY",
+	"request-timeout": "SG_REQUEST_TIMEOUT",
+	"max-attempts":    "SG_MAX_ATTEMPTS",
+}
+
+// Dir returns the directory holding the sg-cli config files
+func Dir() string {
+	if path := os.Getenv("SG_CONFIG_FILE"); path != "" {
+		return filepath.Dir(path)
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "sg-cli")
+}
+
+// Path returns the location of the config file. SG_CONFIG_FILE overrides the default location.
+func Path() string {
+	if path := os.Getenv("SG_CONFIG_FILE"); path != "" {
+		return path
+	}
+	return filepath.Join(Dir(), "config.yaml")
+}
+
+// Load reads the config file. A missing file is not an error and yields an empty config.
+func Load() (*File, error) {
+	f := &File{Profiles: map[string]*Profile{}}
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return f, nil
+		}
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", Path(), err)
+	}
+	if f.Profiles == nil {
+		f.Profiles = map[string]*Profile{}
+	}
+	return f, nil
+}
+
+// Save writes the config file. The file may contain API tokens so it is only readable by the owner.
+func (f *File) Save() error {
+	if err := os.MkdirAll(filepath.Dir(Path()), 0700); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(Path(), data, 0600)
+}
+
+// ActiveProfileName returns the profile to use, in order of precedence:
+// the given override (--profile), SG_PROFILE, current-profile from the file and "default".
+func (f *File) ActiveProfileName(override string) string {
+	if override != "" {
+		return override
+	}
+	if env := os.Getenv("SG_PROFILE"); env != "" {
+		return env
+	}
+	if f.CurrentProfile != "" {
+		return f.CurrentProfile
+	}
+	return DefaultProfile
+}
+
+// Profile returns the named profile, creating an empty one if it does not exist yet
+func (f *File) Profile(name string) *Profile {
+	p, ok := f.Profiles[name]
+	if !ok || p == nil {
+		p = &Profile{}
+		f.Profiles[name] = p
+	}
+	return p
+}
+
+// ProfileNames returns the names of all profiles in the file, sorted
+func (f *File) ProfileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Keys returns all the keys that can be used with Get and Set
+func Keys() []string {
+	var keys []string
+	t := reflect.TypeOf(Profile{})
+	for i := 0; i < t.NumField(); i++ {
+		keys = append(keys, keyOf(t.Field(i)))
+	}
+	return keys
+}
+
+// Get returns the value of the given key
+func (p *Profile) Get(key string) (string, error) {
+	field, err := p.field(key)
+	if err != nil {
+		return "", err
+	}
+	return field.String(), nil
+}
+
+// Set updates the value of the given key
+func (p *Profile) Set(key, value string) error {
+	field, err := p.field(key)
+	if err != nil {
+		return err
+	}
+	field.SetString(value)
+	return nil
+}
+
+func (p *Profile) field(key string) (reflect.Value, error) {
+	v := reflect.ValueOf(p).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if keyOf(v.Type().Field(i)) == key {
+			return v.Field(i), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("unknown config key %q, valid keys are: %s", key, strings.Join(Keys(), ", "))
+}
+
+func keyOf(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("yaml"), ",")[0]
+}
+
+// Settings are the effective values for the current invocation,
+// after merging the selected profile, the environment and the defaults.
+type Settings struct {
+	ProfileName string
+	// ProfileExists is false when the selected profile is not defined in the config file
+	ProfileExists bool
+	Profile
+	// Sources records where the value of each non-empty key came from
+	Sources map[string]string
+}
+
+var current *Settings
+
+// Resolve builds the Settings for the given profile name (see ActiveProfileName).
+// Environment variables take precedence over values from the config file.
+func Resolve(profileName string) (*Settings, error) {
+	f, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	name := f.ActiveProfileName(profileName)
+	s := &Settings{ProfileName: name, Sources: map[string]string{}}
+	if p, ok := f.Profiles[name]; ok && p != nil {
+		s.Profile = *p
+		s.ProfileExists = true
+		for _, key := range Keys() {
+			if value, _ := s.Get(key); value != "" {
+				s.Sources[key] = "profile " + name
+			}
+		}
+	}
+	for key, env := range envOverrides {
+		if value := os.Getenv(env); value != "" {
+			s.Set(key, value)
+			s.Sources[key] = "env " + env
+		}
+	}
+	if s.APIURL == "" {
+		s.APIURL = DefaultAPIURL
+		s.Sources["api-url"] = "default"
+	}
+	if s.DashboardURL == "" {
+		s.DashboardURL = DefaultDashboardURL
+		s.Sources["dashboard-url"] = "default"
+	}
+	s.APIURL = strings.TrimSuffix(s.APIURL, "/")
+	s.DashboardURL = strings.TrimSuffix(s.DashboardURL, "/")
+	return s, nil
+}
+
+// Override sets the value of the given key when it is not empty, for example from a command line flag
+func (s *Settings) Override(key, value, source string) error {
+	if value == "" {
+		return nil
+	}
+	if err := s.Set(key, value); err != nil {
+		return err
+	}
+	s.Sources[key] = source
+	return nil
+}
+
+// SetCurrent makes the given settings the ones returned by Current
+func SetCurrent(s *Settings) {
+	current = s
+}
+
+// Current returns the settings of the running command. If none have been set yet,
+// they are resolved from the environment and the default profile.
+func Current() *Settings {
+	if current == nil {
+		s, err := Resolve("")
+		if err != nil {
+			s = &Settings{
+				ProfileName: DefaultProfile,
+				Profile:     Profile{APIURL: DefaultAPIURL, DashboardURL: DefaultDashboardURL},
+				Sources:     map[string]string{"api-url": "default", "dashboard-url": "default"},
+			}
+		}
+		current = s
+	}
+	return current
+}
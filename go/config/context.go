@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Context is the org and workflow group remembered between invocations, see `sg-cli context`.
+// It takes precedence over the org and workflow group of the profile and the environment.
+type Context struct {
+	Org           string `yaml:"org,omitempty"`
+	WorkflowGroup string `yaml:"workflow-group,omitempty"`
+}
+
+// ContextPath returns the location of the file holding the context of every profile
+func ContextPath() string {
+	return filepath.Join(Dir(), "context.yaml")
+}
+
+func loadContexts() (map[string]*Context, error) {
+	contexts := map[string]*Context{}
+	data, err := os.ReadFile(ContextPath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return contexts, nil
+		}
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &contexts); err != nil {
+		return nil, err
+	}
+	return contexts, nil
+}
+
+func saveContexts(contexts map[string]*Context) error {
+	if err := os.MkdirAll(Dir(), 0700); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(contexts)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(ContextPath(), data, 0600)
+}
+
+// LoadContext returns the context stored for the given profile, an empty context if there is none
+func LoadContext(profileName string) (*Context, error) {
+	contexts, err := loadContexts()
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := contexts[profileName]; ok && c != nil {
+		return c, nil
+	}
+	return &Context{}, nil
+}
+
+// SaveContext stores the context for the given profile
+func SaveContext(profileName string, c *Context) error {
+	contexts, err := loadContexts()
+	if err != nil {
+		return err
+	}
+	contexts[profileName] = c
+	return saveContexts(contexts)
+}
+
+// ClearContext removes the context stored for the given profile
+func ClearContext(profileName string) error {
+	contexts, err := loadContexts()
+	if err != nil {
+		return err
+	}
+	delete(contexts, profileName)
+	return saveContexts(contexts)
+}
@@ -0,0 +1,104 @@
+package dashboard
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/StackGuardian/sg-cli/config"
+)
+
+// Links builds URLs to resources on the Stackguardian dashboard.
+// All commands build their links through it so self-hosted and QA tenants get correct links.
+type Links struct {
+	BaseURL string
+}
+
+// New returns the link builder for the dashboard at baseURL
+func New(baseURL string) *Links {
+	return &Links{BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Default returns the link builder for the dashboard of the current settings (dashboard-url / SG_DASHBOARD_URL)
+func Default() *Links {
+	return New(config.Current().DashboardURL)
+}
+
+func (l *Links) path(segments ...string) string {
+	escaped := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		escaped = append(escaped, url.PathEscape(segment))
+	}
+	return l.BaseURL + "/" + strings.Join(escaped, "/")
+}
+
+// Org returns the link to the organization overview
+func (l *Links) Org(org string) string {
+	return l.path("orgs", org)
+}
+
+// WorkflowGroup returns the link to a workflow group
+func (l *Links) WorkflowGroup(org, wfGrp string) string {
+	return l.path("orgs", org, "wfgrps", wfGrp)
+}
+
+// Workflow returns the link to a workflow
+func (l *Links) Workflow(org, wfGrp, wf string) string {
+	return l.path("orgs", org, "wfgrps", wfGrp, "wfs", wf)
+}
+
+// WorkflowRuns returns the link to the runs tab of a workflow
+func (l *Links) WorkflowRuns(org, wfGrp, wf string) string {
+	return l.Workflow(org, wfGrp, wf) + "?tab=runs"
+}
+
+// WorkflowRun returns the link to a single workflow run
+func (l *Links) WorkflowRun(org, wfGrp, wf, wfRun string) string {
+	return l.path("orgs", org, "wfgrps", wfGrp, "wfs", wf, "wfruns", wfRun)
+}
+
+// Artifacts returns the link to the artifacts tab of a workflow
+func (l *Links) Artifacts(org, wfGrp, wf string) string {
+	return l.Workflow(org, wfGrp, wf) + "?tab=artifacts"
+}
+
+// Stack returns the link to a stack
+func (l *Links) Stack(org, wfGrp, stack string) string {
+	return l.path("orgs", org, "wfgrps", wfGrp, "stacks", stack)
+}
+
+// StackRuns returns the link to the runs tab of a stack
+func (l *Links) StackRuns(org, wfGrp, stack string) string {
+	return l.Stack(org, wfGrp, stack) + "?tab=runs"
+}
+
+// StackRun returns the link to a single stack run
+func (l *Links) StackRun(org, wfGrp, stack, stackRun string) string {
+	return l.path("orgs", org, "wfgrps", wfGrp, "stacks", stack, "stackruns", stackRun)
+}
+
+// StackWorkflowRun returns the link to a workflow run started by a stack run
+func (l *Links) StackWorkflowRun(org, wfGrp, stack, wf, wfRun string) string {
+	return l.path("orgs", org, "wfgrps", wfGrp, "stacks", stack, "wfs", wf, "wfruns", wfRun)
+}
+
+// Integrations returns the link to the integrations tab of the organization,
+// filtered on a single integration when name is not empty
+func (l *Links) Integrations(org, name string) string {
+	if name == "" {
+		return l.Org(org) + "?tab=integrations"
+	}
+	return l.Org(org) + "?integrations=&tab=integrations&integration-name=" + url.QueryEscape(name)
+}
+
+// Settings returns the link to a tab of the organization settings
+func (l *Links) Settings(org, tab string) string {
+	if tab == "" {
+		return l.path("orgs", org, "settings")
+	}
+	return l.path("orgs", org, "settings") + "?tab=" + url.QueryEscape(tab)
+}
+
+// APIKeySettings returns the link to the page where users create their API token
+func (l *Links) APIKeySettings(org string) string {
+	return l.Settings(org, "api_key")
+}
@@ -0,0 +1,285 @@
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Identities identify the items of the arrays of a workflow, e.g. the environment variables by their name
+var Identities = []string{"config.varName", "name"}
+
+// Redacted replaces the values of secrets in the changes, see Mask
+const Redacted = "(sensitive)"
+
+// sensitive matches the keys and the variable names that hold secrets
+var sensitive = regexp.MustCompile(`(?i)secret|password|passwd|token|credential|private_?key|api_?key`)
+
+// Kinds of a change
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change is a difference between two JSON documents at a path such as $.TerraformConfig.terraformVersion
+type Change struct {
+	Path   string      `json:"path"`
+	Kind   string      `json:"kind"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// Compare returns the changes from before to after, ordered by path.
+// Objects are compared key by key and arrays item by item, other values are compared as a whole.
+func Compare(before, after interface{}) []Change {
+	return CompareBy(before, after)
+}
+
+// CompareBy is Compare, but the arrays of objects that all have a distinct string value at one of the identities,
+// dot separated paths such as config.varName, are compared by that value instead of by position. Their items get
+// paths such as $.EnvironmentVariables[varName=FOO].
+func CompareBy(before, after interface{}, identities ...string) []Change {
+	changes := []Change{}
+	compare("$", before, after, identities, &changes)
+	return changes
+}
+
+func compare(path string, before, after interface{}, identities []string, changes *[]Change) {
+	switch b := before.(type) {
+	case map[string]interface{}:
+		if a, ok := after.(map[string]interface{}); ok {
+			keys := map[string]bool{}
+			for key := range b {
+				keys[key] = true
+			}
+			for key := range a {
+				keys[key] = true
+			}
+			sorted := make([]string, 0, len(keys))
+			for key := range keys {
+				sorted = append(sorted, key)
+			}
+			sort.Strings(sorted)
+			for _, key := range sorted {
+				beforeValue, inBefore := b[key]
+				afterValue, inAfter := a[key]
+				switch {
+				case !inBefore:
+					*changes = append(*changes, Change{Path: Key(path, key), Kind: Added, After: afterValue})
+				case !inAfter:
+					*changes = append(*changes, Change{Path: Key(path, key), Kind: Removed, Before: beforeValue})
+				default:
+					compare(Key(path, key), beforeValue, afterValue, identities, changes)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if a, ok := after.([]interface{}); ok {
+			if compareItems(path, b, a, identities, changes) {
+				return
+			}
+			for i := 0; i < max(len(b), len(a)); i++ {
+				item := fmt.Sprintf("%s[%d]", path, i)
+				switch {
+				case i >= len(b):
+					*changes = append(*changes, Change{Path: item, Kind: Added, After: a[i]})
+				case i >= len(a):
+					*changes = append(*changes, Change{Path: item, Kind: Removed, Before: b[i]})
+				default:
+					compare(item, b[i], a[i], identities, changes)
+				}
+			}
+			return
+		}
+	}
+	if !equal(before, after) {
+		*changes = append(*changes, Change{Path: path, Kind: Changed, Before: before, After: after})
+	}
+}
+
+// compareItems compares two arrays by the first identity that all their items have, it reports false if there is none
+func compareItems(path string, before, after []interface{}, identities []string, changes *[]Change) bool {
+	for _, identity := range identities {
+		beforeItems, okBefore := index(before, identity)
+		afterItems, okAfter := index(after, identity)
+		if !okBefore || !okAfter {
+			continue
+		}
+		values := make([]string, 0, len(beforeItems)+len(afterItems))
+		for value := range beforeItems {
+			values = append(values, value)
+		}
+		for value := range afterItems {
+			if _, ok := beforeItems[value]; !ok {
+				values = append(values, value)
+			}
+		}
+		sort.Strings(values)
+		label := identity[strings.LastIndex(identity, ".")+1:]
+		for _, value := range values {
+			item := path + "[" + label + "=" + value + "]"
+			beforeItem, inBefore := beforeItems[value]
+			afterItem, inAfter := afterItems[value]
+			switch {
+			case !inBefore:
+				*changes = append(*changes, Change{Path: item, Kind: Added, After: afterItem})
+			case !inAfter:
+				*changes = append(*changes, Change{Path: item, Kind: Removed, Before: beforeItem})
+			default:
+				compare(item, beforeItem, afterItem, identities, changes)
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// index returns the items of an array by their string value at identity, false if one of them has none or
+// two of them have the same
+func index(items []interface{}, identity string) (map[string]interface{}, bool) {
+	indexed := make(map[string]interface{}, len(items))
+	for _, item := range items {
+		value := item
+		for _, key := range strings.Split(identity, ".") {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			value = object[key]
+		}
+		name, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+		if _, duplicate := indexed[name]; duplicate {
+			return nil, false
+		}
+		indexed[name] = item
+	}
+	return indexed, true
+}
+
+// equal compares two decoded JSON values, numbers by their value
+func equal(a, b interface{}) bool {
+	left, errLeft := json.Marshal(a)
+	right, errRight := json.Marshal(b)
+	return errLeft == nil && errRight == nil && string(left) == string(right)
+}
+
+// identifier matches the keys that can follow a dot in a path
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// Key returns the path of key in the object at path
+func Key(path, key string) string {
+	if identifier.MatchString(key) {
+		return path + "." + key
+	}
+	quoted, _ := json.Marshal(key)
+	return path + "[" + string(quoted) + "]"
+}
+
+// Mask redacts the secrets in changes: the values of sensitive keys and the text values of
+// environment variables with a sensitive name, such as $.EnvironmentVariables[varName=API_TOKEN].config.textValue
+func Mask(changes []Change) []Change {
+	for i, change := range changes {
+		if secret(change.Path) {
+			changes[i].Before, changes[i].After = redact(change.Before != nil), redact(change.After != nil)
+			continue
+		}
+		changes[i].Before, changes[i].After = mask(change.Before), mask(change.After)
+	}
+	return changes
+}
+
+// secret reports whether the value at path is a secret
+func secret(path string) bool {
+	last := path[strings.LastIndexAny(path, ".[")+1:]
+	if strings.Contains(last, "=") {
+		// An item of an array such as [varName=API_TOKEN], its secrets are masked by mask
+		return false
+	}
+	return sensitive.MatchString(last) || last == "textValue" && sensitive.MatchString(path)
+}
+
+// redact returns Redacted for a value that is set
+func redact(set bool) interface{} {
+	if set {
+		return Redacted
+	}
+	return nil
+}
+
+// mask returns a copy of value with the secrets it holds redacted
+func mask(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		name, _ := v["varName"].(string)
+		for key, item := range v {
+			if item != nil && (sensitive.MatchString(key) || key == "textValue" && sensitive.MatchString(name)) {
+				masked[key] = Redacted
+				continue
+			}
+			masked[key] = mask(item)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, item := range v {
+			masked[i] = mask(item)
+		}
+		return masked
+	}
+	return value
+}
+
+// Colors of the lines of RenderColor
+const (
+	green  = "\x1b[32m"
+	red    = "\x1b[31m"
+	yellow = "\x1b[33m"
+	reset  = "\x1b[0m"
+)
+
+// Render prints one line per change: "+ path: value" when added, "- path: value" when removed
+// and "~ path: before -> after" when changed
+func Render(changes []Change) string {
+	return render(changes, false)
+}
+
+// RenderColor is Render with added lines in green, removed lines in red and changed lines in yellow
+func RenderColor(changes []Change) string {
+	return render(changes, true)
+}
+
+func render(changes []Change, color bool) string {
+	lines := make([]string, 0, len(changes))
+	for _, change := range changes {
+		var line, code string
+		switch change.Kind {
+		case Added:
+			line, code = "+ "+change.Path+": "+value(change.After), green
+		case Removed:
+			line, code = "- "+change.Path+": "+value(change.Before), red
+		default:
+			line, code = "~ "+change.Path+": "+value(change.Before)+" -> "+value(change.After), yellow
+		}
+		if color {
+			line = code + line + reset
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func value(v interface{}) string {
+	out, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(out)
+}
@@ -0,0 +1,130 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONPath renders a kubectl style JSONPath template, e.g. "{.msg[*].ResourceName}".
+// Text outside of braces is printed as is. Expressions support fields (.name or ['name']),
+// indexes ([0], [-1]) and wildcards ([*] or .*); multiple results are separated by spaces.
+func JSONPath(template string, doc interface{}) (string, error) {
+	var out strings.Builder
+	for template != "" {
+		start := strings.Index(template, "{")
+		if start < 0 {
+			out.WriteString(template)
+			break
+		}
+		out.WriteString(template[:start])
+		end := strings.Index(template[start:], "}")
+		if end < 0 {
+			return "", errors.New("unclosed {")
+		}
+		results, err := evaluate(strings.TrimSpace(template[start+1:start+end]), doc)
+		if err != nil {
+			return "", err
+		}
+		values := make([]string, 0, len(results))
+		for _, result := range results {
+			values = append(values, Cell(result))
+		}
+		out.WriteString(strings.Join(values, " "))
+		template = template[start+end+1:]
+	}
+	return out.String(), nil
+}
+
+// evaluate returns the values selected by a single expression
+func evaluate(expression string, doc interface{}) ([]interface{}, error) {
+	expression = strings.TrimPrefix(expression, "$")
+	results := []interface{}{doc}
+	for expression != "" {
+		var step func(interface{}) []interface{}
+		switch expression[0] {
+		case '.':
+			name := expression[1:]
+			if i := strings.IndexAny(name, ".["); i >= 0 {
+				name = name[:i]
+			}
+			expression = expression[1+len(name):]
+			if name == "" {
+				continue
+			}
+			step = field(name)
+		case '[':
+			end := strings.Index(expression, "]")
+			if end < 0 {
+				return nil, errors.New("unclosed [")
+			}
+			selector := strings.TrimSpace(expression[1:end])
+			expression = expression[end+1:]
+			var err error
+			step, err = bracket(selector)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unexpected %q, expressions start with . or [", expression)
+		}
+		var next []interface{}
+		for _, result := range results {
+			next = append(next, step(result)...)
+		}
+		results = next
+	}
+	return results, nil
+}
+
+func bracket(selector string) (func(interface{}) []interface{}, error) {
+	if selector == "*" {
+		return field("*"), nil
+	}
+	if len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0] {
+		return field(selector[1 : len(selector)-1]), nil
+	}
+	index, err := strconv.Atoi(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector [%s]", selector)
+	}
+	return func(value interface{}) []interface{} {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		i := index
+		if i < 0 {
+			i += len(items)
+		}
+		if i < 0 || i >= len(items) {
+			return nil
+		}
+		return []interface{}{items[i]}
+	}, nil
+}
+
+// field selects the named field of objects, or all children for "*"
+func field(name string) func(interface{}) []interface{} {
+	return func(value interface{}) []interface{} {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if name == "*" {
+				children := make([]interface{}, 0, len(v))
+				for _, key := range sortedKeys(v) {
+					children = append(children, v[key])
+				}
+				return children
+			}
+			if child, ok := v[name]; ok {
+				return []interface{}{child}
+			}
+		case []interface{}:
+			if name == "*" {
+				return v
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,296 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats lists the values accepted by --output. jsonpath and go-template take their template after "=".
+var Formats = []string{"json", "yaml", "table", "wide", "csv", "jsonl", "name", "jsonpath=<template>", "go-template=<template>"}
+
+// AddFlags adds --output and the deprecated --output-json to cmd and all of its sub-commands
+func AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP("output", "o", "", "Output format: "+strings.Join(Formats, ", ")+". "+
+		"Human readable messages are printed to stderr when a format is set.")
+	cmd.PersistentFlags().Bool("output-json", false, "Output execution response as json.")
+	cmd.PersistentFlags().MarkDeprecated("output-json", "use --output json instead")
+}
+
+// Printer prints API responses in the format chosen with --output
+type Printer struct {
+	format   string
+	template string
+	out      io.Writer
+	errOut   io.Writer
+	cmd      *cobra.Command
+}
+
+// New returns the printer for the --output flag of cmd
+func New(cmd *cobra.Command) (*Printer, error) {
+	p := &Printer{out: cmd.OutOrStdout(), errOut: cmd.ErrOrStderr(), cmd: cmd}
+	if flag := cmd.Flags().Lookup("output-json"); flag != nil && flag.Value.String() == "true" {
+		p.format = "json"
+	}
+	flag := cmd.Flags().Lookup("output")
+	if flag == nil || flag.Value.String() == "" {
+		return p, nil
+	}
+	p.format, p.template, _ = strings.Cut(flag.Value.String(), "=")
+	switch p.format {
+	case "json", "yaml", "table", "wide", "csv", "jsonl", "name":
+		if p.template != "" {
+			return nil, clierrors.New(clierrors.Usage, "output format %q does not take a template", p.format)
+		}
+	case "jsonpath", "go-template":
+		if p.template == "" {
+			return nil, clierrors.New(clierrors.Usage, "output format %s requires a template, e.g. -o %s=%s", p.format, p.format, example(p.format))
+		}
+	default:
+		return nil, clierrors.New(clierrors.Usage, "unknown output format %q, valid formats are: %s", flag.Value.String(), strings.Join(Formats, ", "))
+	}
+	return p, nil
+}
+
+func example(format string) string {
+	if format == "jsonpath" {
+		return "'{.msg.ResourceName}'"
+	}
+	return "'{{.msg.ResourceName}}'"
+}
+
+// Structured reports whether a format was chosen. Human readable messages then go to stderr, see Println.
+func (p *Printer) Structured() bool {
+	return p.format != ""
+}
+
+// JSON reports whether the chosen format is json or jsonl
+func (p *Printer) JSON() bool {
+	return p.format == "json" || p.format == "jsonl"
+}
+
+// Println prints a human readable message. It goes to stderr when a format was chosen to keep stdout parseable.
+func (p *Printer) Println(i ...interface{}) {
+	if p.Structured() {
+		fmt.Fprintln(p.errOut, i...)
+		return
+	}
+	p.cmd.Println(i...)
+}
+
+// Text returns the writer for plain text output such as logs: stdout, or stderr when a format was chosen
+func (p *Printer) Text() io.Writer {
+	if p.Structured() {
+		return p.errOut
+	}
+	return p.out
+}
+
+// Print prints an API response as the resource r in the chosen format, JSON if none was chosen
+func (p *Printer) Print(value interface{}, r Resource) error {
+	return p.PrintDefault(value, r, "json")
+}
+
+// PrintDefault prints an API response as the resource r in the chosen format, or in format if none was chosen
+func (p *Printer) PrintDefault(value interface{}, r Resource, format string) error {
+	if p.format != "" {
+		format = p.format
+	}
+	raw, err := rawJSON(value)
+	if err != nil {
+		return err
+	}
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, raw, "", "  "); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.out, indented.String())
+		return err
+	case "yaml":
+		out, err := yaml.Marshal(numbers(doc))
+		if err != nil {
+			return err
+		}
+		_, err = p.out.Write(out)
+		return err
+	case "jsonl":
+		for _, row := range r.rows(doc) {
+			line, err := json.Marshal(row)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(p.out, string(line))
+		}
+		return nil
+	case "name":
+		for _, row := range r.rows(doc) {
+			fmt.Fprintln(p.out, Cell(lookup(row, r.name())))
+		}
+		return nil
+	case "table", "wide":
+		return p.table(doc, r, format == "wide")
+	case "csv":
+		return p.csv(doc, r)
+	case "jsonpath":
+		out, err := JSONPath(p.template, doc)
+		if err != nil {
+			return clierrors.New(clierrors.Usage, "invalid jsonpath template: %s", err)
+		}
+		_, err = fmt.Fprintln(p.out, out)
+		return err
+	case "go-template":
+		tmpl, err := template.New("output").Funcs(template.FuncMap{"json": toJSON}).Parse(p.template)
+		if err != nil {
+			return clierrors.New(clierrors.Usage, "invalid go-template: %s", err)
+		}
+		return tmpl.Execute(p.out, doc)
+	}
+	return nil
+}
+
+func (p *Printer) table(doc interface{}, r Resource, wide bool) error {
+	rows := r.rows(doc)
+	if len(rows) == 0 {
+		fmt.Fprintln(p.errOut, "No resources found.")
+		return nil
+	}
+	columns := r.columns(wide)
+	w := tabwriter.NewWriter(p.out, 0, 8, 3, ' ', 0)
+	headers := make([]string, 0, len(columns))
+	for _, column := range columns {
+		headers = append(headers, column.Header)
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		cells := make([]string, 0, len(columns))
+		for _, column := range columns {
+			value := strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(column.value(row))
+			cells = append(cells, value)
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	return w.Flush()
+}
+
+func (p *Printer) csv(doc interface{}, r Resource) error {
+	columns := r.columns(true)
+	w := csv.NewWriter(p.out)
+	headers := make([]string, 0, len(columns))
+	for _, column := range columns {
+		headers = append(headers, column.Header)
+	}
+	w.Write(headers)
+	for _, row := range r.rows(doc) {
+		cells := make([]string, 0, len(columns))
+		for _, column := range columns {
+			cells = append(cells, column.value(row))
+		}
+		w.Write(cells)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// rawJSON returns the JSON of an API response. SDK responses print the JSON they were decoded from,
+// which keeps fields the SDK does not know about.
+func rawJSON(value interface{}) ([]byte, error) {
+	if value == nil {
+		return []byte("null"), nil
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer && v.IsNil() {
+		return []byte("null"), nil
+	}
+	if stringer, ok := value.(fmt.Stringer); ok {
+		if s := stringer.String(); json.Valid([]byte(s)) {
+			return []byte(s), nil
+		}
+	}
+	return json.Marshal(value)
+}
+
+// Cell formats a value for a table cell
+func Cell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, Cell(item))
+		}
+		return strings.Join(values, ",")
+	default:
+		return toJSON(v)
+	}
+}
+
+// numbers replaces the json.Number values of doc by int64 or float64, which other encoders understand
+func numbers(doc interface{}) interface{} {
+	switch v := doc.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			converted[key] = numbers(value)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, 0, len(v))
+		for _, value := range v {
+			converted = append(converted, numbers(value))
+		}
+		return converted
+	}
+	return doc
+}
+
+func toJSON(value interface{}) string {
+	out, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(out)
+}
+
+// sortedKeys returns the keys of m in a stable order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,311 @@
+package output
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Resource describes how an API response is split into rows for the table, wide, csv, jsonl and name formats
+type Resource struct {
+	// Items is the dot separated path to the rows in the response, e.g. "msg". A single object is one row.
+	Items string
+	// Keyed rows come from an object whose keys identify them, e.g. artifacts by path.
+	// Each row is then {"key": <key>, "value": <value>}.
+	Keyed bool
+	// Name is the path to the value printed by -o name, ResourceName by default
+	Name    string
+	Columns []Column
+}
+
+// Column is a table column. Wide columns are only shown with -o wide and -o csv.
+type Column struct {
+	Header string
+	// Path is the dot separated path to the value in a row, an empty path passes the whole row to Format
+	Path string
+	Wide bool
+	// Format formats the value of the column, Cell by default
+	Format func(value interface{}) string
+}
+
+// Workflow is a workflow or the list of workflows of a workflow group
+var Workflow = Resource{
+	Items: "msg",
+	Columns: []Column{
+		{Header: "NAME", Path: "ResourceName"},
+		{Header: "TYPE", Path: "WfType"},
+		{Header: "STATUS", Path: "LatestWfrunStatus"},
+		{Header: "TAGS", Path: "Tags"},
+		{Header: "MODIFIED", Path: "ModifiedAt", Format: Timestamp},
+		{Header: "DESCRIPTION", Path: "Description"},
+		{Header: "REPO", Path: "GitHubComRepoID", Wide: true},
+		{Header: "ID", Path: "ResourceId", Wide: true},
+		{Header: "AUTHORS", Path: "Authors", Wide: true},
+		{Header: "CREATED", Path: "CreatedAt", Wide: true, Format: Timestamp},
+	},
+}
+
+// WorkflowRun is the response of a created workflow run
+var WorkflowRun = Resource{
+	Items: "data",
+	Columns: []Column{
+		{Header: "RUN", Path: "ResourceName"},
+		{Header: "STATUS", Path: "LatestStatus"},
+		{Header: "CREATED", Path: "CreatedAt", Format: Timestamp},
+		{Header: "ID", Path: "SubResourceId", Wide: true},
+		{Header: "AUTHORS", Path: "Authors", Wide: true},
+	},
+}
+
+// WorkflowRunStatus is a workflow run as read from the API
+var WorkflowRunStatus = Resource{
+	Items: "msg",
+	Columns: []Column{
+		{Header: "RUN", Path: "ResourceName"},
+		{Header: "STATUS", Path: "LatestStatus"},
+		{Header: "CREATED", Path: "CreatedAt", Format: Timestamp},
+		{Header: "MODIFIED", Path: "ModifiedAt", Format: Timestamp},
+		{Header: "ID", Path: "SubResourceId", Wide: true},
+		{Header: "AUTHORS", Path: "Authors", Wide: true},
+	},
+}
+
+// WorkflowRuns are the runs of a workflow, or a single run read from the API
+var WorkflowRuns = Resource{
+	Items: "msg",
+	Columns: []Column{
+		{Header: "RUN ID", Path: "ResourceName"},
+		{Header: "ACTION", Path: "RuntimeParameters.terraformAction.action"},
+		{Header: "STATUS", Path: "LatestStatus"},
+		{Header: "INITIATOR", Path: "Authors", Format: First},
+		{Header: "STARTED", Path: "CreatedAt", Format: Timestamp},
+		{Header: "DURATION", Format: RunDuration},
+		{Header: "COMMIT", Format: Commit},
+		{Header: "ID", Path: "SubResourceId", Wide: true},
+	},
+}
+
+// Approvals are the results of approving or rejecting workflow runs
+var Approvals = Resource{
+	Items: "msg",
+	Name:  "run",
+	Columns: []Column{
+		{Header: "WORKFLOW", Path: "workflow"},
+		{Header: "RUN", Path: "run"},
+		{Header: "STATUS", Path: "status"},
+		{Header: "PLAN", Path: "plan"},
+		{Header: "RESULT", Path: "result"},
+		{Header: "STACK", Path: "stack", Wide: true},
+	},
+}
+
+// PlanChanges are the resource changes of a plan
+var PlanChanges = Resource{
+	Items: "msg",
+	Name:  "address",
+	Columns: []Column{
+		{Header: "ACTION", Path: "action"},
+		{Header: "ADDRESS", Path: "address"},
+	},
+}
+
+// WorkflowDiff are the differences between a workflow and a payload
+var WorkflowDiff = Resource{
+	Items: "msg",
+	Name:  "path",
+	Columns: []Column{
+		{Header: "KIND", Path: "kind"},
+		{Header: "PATH", Path: "path"},
+		{Header: "BEFORE", Path: "before", Wide: true},
+		{Header: "AFTER", Path: "after", Wide: true},
+	},
+}
+
+// Created is the response of a created workflow or stack
+var Created = Resource{
+	Items: "data",
+	Columns: []Column{
+		{Header: "NAME", Path: "ResourceName"},
+		{Header: "TYPE", Path: "ResourceType"},
+		{Header: "CREATED", Path: "CreatedAt", Format: Timestamp},
+		{Header: "ID", Path: "SubResourceId", Wide: true},
+		{Header: "AUTHORS", Path: "Authors", Wide: true},
+	},
+}
+
+// StackRun is the response of a stack run, one row per workflow run
+var StackRun = Resource{
+	Items: "data.workflowruns",
+	Columns: []Column{
+		{Header: "RUN", Path: "ResourceName"},
+		{Header: "STATUS", Path: "LatestStatus"},
+		{Header: "CREATED", Path: "CreatedAt", Format: Timestamp},
+		{Header: "ID", Path: "SubResourceId", Wide: true},
+	},
+}
+
+// StackRunStatus is a stack run as read from the API, one row per workflow run
+var StackRunStatus = Resource{
+	Items: "msg.WfRuns",
+	Columns: []Column{
+		{Header: "RUN", Path: "ResourceName"},
+		{Header: "STATUS", Path: "LatestStatus"},
+		{Header: "STARTED", Path: "CreatedAt", Format: Timestamp},
+		{Header: "DURATION", Format: RunDuration},
+		{Header: "ID", Path: "SubResourceId", Wide: true},
+	},
+}
+
+// StackOutputs are the outputs of a stack by workflow
+var StackOutputs = Resource{
+	Items: "data",
+	Keyed: true,
+	Columns: []Column{
+		{Header: "WORKFLOW", Path: "key"},
+		{Header: "OUTPUTS", Path: "value"},
+	},
+}
+
+// Artifacts are the artifacts of a workflow by path
+var Artifacts = Resource{
+	Items: "data.artifacts",
+	Keyed: true,
+	Columns: []Column{
+		{Header: "NAME", Path: "key"},
+		{Header: "SIZE", Path: "value.size"},
+		{Header: "LAST MODIFIED", Path: "value.lastModified"},
+		{Header: "URL", Path: "value.url", Wide: true},
+	},
+}
+
+// Message is a response that only carries a message, e.g. of a deletion
+var Message = Resource{
+	Name: "msg",
+	Columns: []Column{
+		{Header: "MESSAGE", Path: "msg"},
+	},
+}
+
+// Timestamp formats milliseconds since the epoch, as returned by the API, in RFC 3339
+func Timestamp(value interface{}) string {
+	millis, err := number(value)
+	if err != nil || millis == 0 {
+		return Cell(value)
+	}
+	return time.UnixMilli(millis).UTC().Format(time.RFC3339)
+}
+
+// First formats the first value of a list, e.g. the initiator of a run from its authors
+func First(value interface{}) string {
+	if values, ok := value.([]interface{}); ok {
+		if len(values) == 0 {
+			return ""
+		}
+		return Cell(values[0])
+	}
+	return Cell(value)
+}
+
+// RunDuration formats the time from the start of a run to its latest status change
+func RunDuration(row interface{}) string {
+	created, errCreated := number(lookup(row, "CreatedAt"))
+	modified, errModified := number(lookup(row, "ModifiedAt"))
+	if errCreated != nil || errModified != nil || modified < created {
+		return ""
+	}
+	return (time.Duration(modified-created) * time.Millisecond).Round(time.Second).String()
+}
+
+// commitPaths are the fields of a run that may hold the VCS commit it ran with, most specific first
+var commitPaths = []string{"TriggerDetails.commitId", "TriggerDetails.commitSha", "VCSConfig.iacVCSConfig.customSource.config.commitId",
+	"RuntimeParameters.vcsConfig.iacVCSConfig.customSource.config.commitId", "VCSConfig.iacVCSConfig.customSource.config.ref"}
+
+// Commit formats the VCS commit or ref of a run, commit hashes are shortened to 12 characters
+func Commit(row interface{}) string {
+	for _, path := range commitPaths {
+		if commit := Cell(lookup(row, path)); commit != "" {
+			if len(commit) == 40 && strings.Trim(commit, "0123456789abcdef") == "" {
+				return commit[:12]
+			}
+			return commit
+		}
+	}
+	return ""
+}
+
+func number(value interface{}) (int64, error) {
+	n, ok := value.(json.Number)
+	if !ok {
+		return 0, strconv.ErrSyntax
+	}
+	if i, err := n.Int64(); err == nil {
+		return i, nil
+	}
+	f, err := n.Float64()
+	return int64(f), err
+}
+
+func (r Resource) name() string {
+	switch {
+	case r.Name != "":
+		return r.Name
+	case r.Keyed:
+		return "key"
+	}
+	return "ResourceName"
+}
+
+func (r Resource) columns(wide bool) []Column {
+	var columns []Column
+	for _, column := range r.Columns {
+		if wide || !column.Wide {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// rows returns the rows of the response
+func (r Resource) rows(doc interface{}) []interface{} {
+	items := lookup(doc, r.Items)
+	switch v := items.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		if !r.Keyed {
+			return []interface{}{v}
+		}
+		rows := make([]interface{}, 0, len(v))
+		for _, key := range sortedKeys(v) {
+			rows = append(rows, map[string]interface{}{"key": key, "value": v[key]})
+		}
+		return rows
+	}
+	return []interface{}{items}
+}
+
+func (c Column) value(row interface{}) string {
+	value := lookup(row, c.Path)
+	if c.Format != nil {
+		return c.Format(value)
+	}
+	return Cell(value)
+}
+
+// lookup returns the value at the dot separated path, nil if there is none
+func lookup(doc interface{}, path string) interface{} {
+	if path == "" {
+		return doc
+	}
+	for _, field := range strings.Split(path, ".") {
+		object, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		doc = object[field]
+	}
+	return doc
+}
@@ -0,0 +1,134 @@
+package runs
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	sggosdk "github.com/StackGuardian/sg-sdk-go"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/spf13/cobra"
+)
+
+// GuardOptions decide what happens when a workflow or stack still has a run in progress before a new run starts
+type GuardOptions struct {
+	IfIdle    bool
+	Queue     bool
+	Supersede bool
+}
+
+// AddGuardFlags adds --if-idle, --queue and --supersede to cmd
+func AddGuardFlags(cmd *cobra.Command, opts *GuardOptions) {
+	cmd.Flags().BoolVar(&opts.IfIdle, "if-idle", false, "Refuse to start the run and exit with 13 when a run is still queued, running or waiting for approval.")
+	cmd.Flags().BoolVar(&opts.Queue, "queue", false, "Wait until the runs in progress finished before starting the run.")
+	cmd.Flags().BoolVar(&opts.Supersede, "supersede", false, "Cancel the runs in progress before starting the run.")
+}
+
+// InProgress reports whether a run with this status blocks a new run
+func InProgress(status string) bool {
+	switch status {
+	case Queued, Running, ApprovalRequired:
+		return true
+	}
+	return false
+}
+
+// ActiveRun is a run in progress
+type ActiveRun struct {
+	ID string
+	// Name of the run in messages, e.g. "Workflow run 1a2b3c"
+	Name   string
+	Status string
+}
+
+// ListActive returns the runs in progress of a workflow or stack
+type ListActive func(ctx context.Context) ([]ActiveRun, error)
+
+// Cancel cancels a run in progress
+type Cancel func(ctx context.Context, run ActiveRun) error
+
+// Guard checks for runs in progress of name, e.g. "Workflow demo-wf", before a new run starts.
+// It does nothing without a guard flag, fails with Busy for --if-idle, cancels the runs for --supersede
+// and waits until no run is in progress for --queue and --supersede.
+func (o *GuardOptions) Guard(ctx context.Context, name string, list ListActive, cancel Cancel, interval time.Duration, log func(i ...interface{})) error {
+	switch {
+	case !o.IfIdle && !o.Queue && !o.Supersede:
+		return nil
+	case o.IfIdle && (o.Queue || o.Supersede) || o.Queue && o.Supersede:
+		return clierrors.New(clierrors.Usage, "--if-idle, --queue and --supersede cannot be used together")
+	}
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	active, err := list(ctx)
+	if err != nil {
+		return err
+	}
+	if len(active) == 0 {
+		return nil
+	}
+	if o.IfIdle {
+		return clierrors.New(clierrors.Busy, "%s is busy, %s is %s", name, active[0].Name, active[0].Status)
+	}
+	if o.Supersede {
+		for _, run := range active {
+			log(">> Cancelling " + run.Name + " (" + run.Status + ")")
+			if err := cancel(ctx, run); err != nil {
+				return err
+			}
+		}
+	}
+	last := ""
+	for len(active) > 0 {
+		if waiting := active[0].Name + " (" + active[0].Status + ")"; waiting != last {
+			log(">> [" + time.Now().Format("2006-01-02 15:04:05") + "] " + name + " is busy, waiting for " + waiting + " to finish")
+			last = waiting
+		}
+		if err := Sleep(ctx, interval); err != nil {
+			return clierrors.Wrap(err, "Stopped waiting for %s to become idle", name)
+		}
+		if active, err = list(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WorkflowActiveRuns lists the runs in progress of a workflow. It reads the status of the latest run first
+// and only lists the latest page of runs when that run is in progress, so a check costs one or two calls.
+func WorkflowActiveRuns(c *client.Client, org, wfGrp, wf string) ListActive {
+	return func(ctx context.Context) ([]ActiveRun, error) {
+		workflow, err := c.Workflows.ReadWorkflow(ctx, org, wf, wfGrp)
+		if err != nil {
+			return nil, clierrors.Wrap(err, "Failed to read workflow %s", wf)
+		}
+		if workflow.Msg == nil || !InProgress(workflow.Msg.LatestWfrunStatus) {
+			return nil, nil
+		}
+		page, err := c.WorkflowRuns.ListAllWorkflowRuns(ctx, org, wf, wfGrp, &sggosdk.ListAllWorkflowRunsRequest{})
+		if err != nil {
+			if strings.Contains(err.Error(), "the server responded with nothing") {
+				return nil, nil
+			}
+			return nil, clierrors.Wrap(err, "Failed to list the runs of workflow %s", wf)
+		}
+		active := []ActiveRun{}
+		for _, run := range page.Msg {
+			if InProgress(run.LatestStatus) {
+				active = append(active, ActiveRun{ID: run.ResourceName, Name: "Workflow run " + run.ResourceName, Status: run.LatestStatus})
+			}
+		}
+		return active, nil
+	}
+}
+
+// CancelWorkflowRun cancels a run of a workflow
+func CancelWorkflowRun(c *client.Client, org, wfGrp, wf string) Cancel {
+	return func(ctx context.Context, run ActiveRun) error {
+		if _, err := c.WorkflowRuns.CancelWorkflowRun(ctx, org, wf, wfGrp, run.ID); err != nil {
+			return clierrors.Wrap(err, "Failed to cancel workflow run %s", run.ID)
+		}
+		return nil
+	}
+}
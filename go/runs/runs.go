@@ -0,0 +1,155 @@
+// Package runs follows workflow and stack runs until they finish
+package runs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+)
+
+// Statuses of workflow runs
+const (
+	Queued           = "QUEUED"
+	Running          = "RUNNING"
+	ApprovalRequired = "APPROVAL_REQUIRED"
+	Completed        = "COMPLETED"
+	Errored          = "ERRORED"
+	Failed           = "FAILED"
+	Cancelled        = "CANCELLED"
+	Rejected         = "REJECTED"
+)
+
+// DefaultInterval is the time between two polls of a run
+const DefaultInterval = 10 * time.Second
+
+// Terminal reports whether a run with this status will not change anymore
+func Terminal(status string) bool {
+	switch status {
+	case Completed, Errored, Failed, Cancelled, Rejected:
+		return true
+	}
+	return false
+}
+
+// Poll returns the current status of a run
+type Poll func(ctx context.Context) (string, error)
+
+// WatchOptions configure Watch
+type WatchOptions struct {
+	// Name of the run in the status lines, e.g. "Workflow run 1a2b3c"
+	Name     string
+	Interval time.Duration
+	// Until are statuses that end the watch before the run is terminal, e.g. APPROVAL_REQUIRED
+	Until []string
+	// Log prints a status line
+	Log func(i ...interface{})
+}
+
+// Watch polls a run and logs every status transition with a timestamp.
+// It returns the last status once the run is terminal or reached one of the Until statuses.
+func Watch(ctx context.Context, poll Poll, opts WatchOptions) (string, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	last := ""
+	for {
+		status, err := poll(ctx)
+		if err != nil {
+			return last, err
+		}
+		if status != last {
+			opts.Log(">> [" + time.Now().Format("2006-01-02 15:04:05") + "] " + opts.Name + ": " + status)
+			last = status
+		}
+		if Terminal(status) || Reached(status, opts.Until) {
+			return status, nil
+		}
+		if err := Sleep(ctx, opts.Interval); err != nil {
+			return last, err
+		}
+	}
+}
+
+// Sleep waits for d or until ctx is done
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Reached reports whether status is one of the until statuses
+func Reached(status string, until []string) bool {
+	for _, u := range until {
+		if strings.EqualFold(status, u) {
+			return true
+		}
+	}
+	return false
+}
+
+// Outcome returns the error for the final status of a run, nil if the run succeeded or reached one of the until statuses
+func Outcome(name, status string, until []string) error {
+	if Reached(status, until) {
+		return nil
+	}
+	switch status {
+	case Completed:
+		return nil
+	case Cancelled, Rejected:
+		return clierrors.New(clierrors.RunCancelled, "%s finished with status %s", name, status)
+	case Errored, Failed:
+		return clierrors.New(clierrors.RunFailed, "%s finished with status %s", name, status)
+	}
+	return clierrors.New(clierrors.RunFailed, "%s stopped with status %s", name, status)
+}
+
+// ParseTime parses a date, an RFC 3339 time or a duration before now. Durations may use d for days.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return time.Time{}, fmt.Errorf("%q is not a number of days", value)
+		}
+		return now.AddDate(0, 0, -n), nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("%q is neither a date, an RFC 3339 time nor a duration", value)
+	}
+	return now.Add(-d), nil
+}
+
+// WorkflowOf returns the workflow ID of a workflow run of a stack run, read from its ID fields
+func WorkflowOf(run map[string]interface{}) string {
+	for _, key := range []string{"WfId", "wfId", "Workflow"} {
+		if id, _ := run[key].(string); id != "" {
+			return id
+		}
+	}
+	for _, key := range []string{"ParentId", "ResourceId", "SubResourceId"} {
+		id, _ := run[key].(string)
+		if _, rest, found := strings.Cut(id, "/wfs/"); found {
+			id, _, _ = strings.Cut(rest, "/")
+			return id
+		}
+	}
+	return ""
+}
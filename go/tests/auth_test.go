@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/StackGuardian/sg-cli/auth"
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthCredentials(t *testing.T) {
+	t.Setenv("SG_CONFIG_FILE", filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv("SG_API_TOKEN", "")
+	t.Setenv("SG_CREDENTIALS_PASSPHRASE", "")
+
+	t.Run("Plain_Token", func(t *testing.T) {
+		assert.NoError(t, auth.SaveCredential("plain", "sgu_plain_token", ""))
+		info, err := os.Stat(auth.CredentialsPath())
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+		settings, err := config.Resolve("plain")
+		assert.NoError(t, err)
+		assert.NoError(t, auth.LoadToken(settings))
+		assert.Equal(t, "sgu_plain_token", settings.APIToken)
+		assert.Equal(t, auth.SourceCredentials, settings.Sources["api-token"])
+		assert.NoError(t, auth.CheckToken(settings))
+	})
+
+	t.Run("Encrypted_Token", func(t *testing.T) {
+		assert.NoError(t, auth.SaveCredential("encrypted", "sgu_secret_token", "passphrase"))
+		credential, err := auth.LoadCredential("encrypted")
+		assert.NoError(t, err)
+		assert.True(t, credential.Encrypted())
+		assert.Empty(t, credential.Token)
+
+		token, err := credential.Decrypt("passphrase")
+		assert.NoError(t, err)
+		assert.Equal(t, "sgu_secret_token", token)
+		_, err = credential.Decrypt("wrong")
+		assert.Error(t, err)
+
+		t.Setenv("SG_CREDENTIALS_PASSPHRASE", "passphrase")
+		settings, err := config.Resolve("encrypted")
+		assert.NoError(t, err)
+		assert.NoError(t, auth.LoadToken(settings))
+		assert.Equal(t, "sgu_secret_token", settings.APIToken)
+	})
+
+	t.Run("Env_Token_Wins", func(t *testing.T) {
+		t.Setenv("SG_API_TOKEN", "sgu_env_token")
+		settings, err := config.Resolve("plain")
+		assert.NoError(t, err)
+		assert.NoError(t, auth.LoadToken(settings))
+		assert.Equal(t, "sgu_env_token", settings.APIToken)
+	})
+
+	t.Run("Invalid_Tokens", func(t *testing.T) {
+		settings, err := config.Resolve("missing")
+		assert.NoError(t, err)
+		assert.ErrorContains(t, auth.CheckToken(settings), "no API token found")
+		settings.APIToken = "not-a-token"
+		assert.ErrorContains(t, auth.CheckToken(settings), "start with")
+	})
+
+	t.Run("Logout", func(t *testing.T) {
+		deleted, err := auth.DeleteCredential("plain")
+		assert.NoError(t, err)
+		assert.True(t, deleted)
+		credential, err := auth.LoadCredential("plain")
+		assert.NoError(t, err)
+		assert.Nil(t, credential)
+	})
+}
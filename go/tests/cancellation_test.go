@@ -0,0 +1,42 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	workflowcmd "github.com/StackGuardian/sg-cli/cmd/workflow"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	option "github.com/StackGuardian/sg-sdk-go/option"
+	"github.com/stretchr/testify/assert"
+)
+
+type contextKey struct{}
+
+// contextTransport records the value of contextKey in the context of every request
+type contextTransport struct {
+	values []interface{}
+}
+
+func (c *contextTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	c.values = append(c.values, request.Context().Value(contextKey{}))
+	return &http.Response{
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{"msg": []}`))),
+		StatusCode: http.StatusOK,
+	}, nil
+}
+
+func TestCancellation(t *testing.T) {
+	t.Run("Requests_Use_Command_Context", func(t *testing.T) {
+		transport := &contextTransport{}
+		cmd := workflowcmd.NewWorkflowCmd(client.NewClient(option.WithHTTPClient(&http.Client{Transport: transport})))
+		cmd.SetArgs([]string{"list", "--org", "not-an-actual-org", "--workflow-group", "not-an-actual-workflow-group"})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		ctx := context.WithValue(context.Background(), contextKey{}, "root")
+		assert.NoError(t, cmd.ExecuteContext(ctx))
+		assert.Equal(t, []interface{}{"root"}, transport.values)
+	})
+}
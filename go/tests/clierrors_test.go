@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	workflowcmd "github.com/StackGuardian/sg-cli/cmd/workflow"
+	"github.com/StackGuardian/sg-cli/cmd/workflow/create"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	"github.com/StackGuardian/sg-sdk-go/core"
+	option "github.com/StackGuardian/sg-sdk-go/option"
+	"github.com/stretchr/testify/assert"
+)
+
+// statusTransport answers every request with the given status code and body
+type statusTransport struct {
+	statusCode int
+	body       string
+}
+
+func (s *statusTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	return &http.Response{
+		Body:       io.NopCloser(bytes.NewReader([]byte(s.body))),
+		StatusCode: s.statusCode,
+	}, nil
+}
+
+func TestErrorCategories(t *testing.T) {
+	cases := []struct {
+		name             string
+		err              error
+		expectedCategory clierrors.Category
+		expectedExitCode int
+	}{
+		{"Unauthorized", core.NewAPIError(401, errors.New("Unauthorized")), clierrors.Auth, 3},
+		{"Not_Found", core.NewAPIError(404, errors.New("Not found")), clierrors.NotFound, 4},
+		{"Validation", core.NewAPIError(400, errors.New("Invalid payload")), clierrors.Validation, 5},
+		{"Conflict", core.NewAPIError(400, errors.New("Workflow name not unique")), clierrors.Conflict, 6},
+		{"Rate_Limited", core.NewAPIError(429, errors.New("Too many requests")), clierrors.RateLimited, 7},
+		{"Server", core.NewAPIError(503, errors.New("Service unavailable")), clierrors.Server, 8},
+		{"Wrapped", clierrors.Wrap(core.NewAPIError(403, errors.New("Forbidden")), "Failed to list workflows"), clierrors.Auth, 3},
+		{"Timeout", fmt.Errorf("request failed: %w", context.DeadlineExceeded), clierrors.Timeout, 124},
+		{"Cancelled", context.Canceled, clierrors.Cancelled, 130},
+		{"General", errors.New("something went wrong"), clierrors.General, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedCategory, clierrors.From(tc.err).Category)
+			assert.Equal(t, tc.expectedExitCode, clierrors.ExitCode(tc.err))
+		})
+	}
+
+	t.Run("Envelope", func(t *testing.T) {
+		b := &bytes.Buffer{}
+		err := clierrors.Wrap(core.NewAPIError(404, errors.New("Workflow not found")), "Failed to read workflow")
+		assert.NoError(t, clierrors.WriteEnvelope(b, err))
+		var envelope clierrors.Envelope
+		assert.NoError(t, json.Unmarshal(b.Bytes(), &envelope))
+		assert.Equal(t, clierrors.EnvelopeError{
+			Category:   clierrors.NotFound,
+			ExitCode:   4,
+			Message:    "Failed to read workflow: 404: Workflow not found",
+			StatusCode: 404,
+		}, envelope.Error)
+	})
+
+	t.Run("Detailed_Exit_Code", func(t *testing.T) {
+		// With --detailed-exitcode, 2 only means changes and every failure exits with 1
+		assert.Equal(t, 1, clierrors.ExitCode(clierrors.Detailed(clierrors.New(clierrors.Usage, "required flag(s) \"workflow-id\" not set"))))
+		assert.Equal(t, 1, clierrors.ExitCode(clierrors.Detailed(core.NewAPIError(404, errors.New("Workflow not found")))))
+		assert.Equal(t, 1, clierrors.ExitCode(clierrors.Detailed(context.DeadlineExceeded)))
+		assert.Equal(t, 2, clierrors.ExitCode(clierrors.Detailed(clierrors.New(clierrors.Changes, "workflow run plans 3 changes"))))
+
+		b := &bytes.Buffer{}
+		assert.NoError(t, clierrors.WriteEnvelope(b, clierrors.Detailed(clierrors.New(clierrors.RunFailed, "workflow run failed"))))
+		var envelope clierrors.Envelope
+		assert.NoError(t, json.Unmarshal(b.Bytes(), &envelope))
+		assert.Equal(t, clierrors.RunFailed, envelope.Error.Category)
+		assert.Equal(t, 1, envelope.Error.ExitCode)
+	})
+
+	t.Run("Bulk_Partial_Failure", func(t *testing.T) {
+		transport := &statusTransport{statusCode: http.StatusBadRequest, body: `"Invalid workflow"`}
+		cmd := workflowcmd.NewWorkflowCmd(client.NewClient(option.WithHTTPClient(&http.Client{Transport: transport})))
+		cmd.SetArgs([]string{
+			"create",
+			"--org", "not-an-actual-org",
+			"--workflow-group", "not-an-actual-workflow-group",
+			"--bulk",
+			"--", filepath.Join(samplePayloadsDir, "create_single_wf_in_bulk_request.json"),
+		})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		err := cmd.Execute()
+		classified := clierrors.From(err)
+		assert.Equal(t, clierrors.PartialFailure, classified.Category)
+		assert.Equal(t, 10, classified.ExitCode())
+		assert.Equal(t, &create.BulkSummary{Failed: []string{"not-an-actual-workflow"}}, classified.Details)
+	})
+}
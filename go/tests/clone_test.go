@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+const templateWorkflow = `{"msg": {
+    "ResourceName": "not-an-actual-workflow",
+    "ResourceId": "/wfs/not-an-actual-workflow",
+    "Description": "template",
+    "WfType": "TERRAFORM",
+    "DeploymentPlatformConfig": [{"kind": "AWS_RBAC", "config": {"integrationId": "/integrations/aws-prod"}}],
+    "RunnerConstraints": {"type": "private", "names": ["team-runners"]},
+    "TerraformConfig": {"managedTerraformState": true}
+}}`
+
+func TestCloneWorkflow(t *testing.T) {
+	const createPath = "POST /api/v1/orgs/other-org/wfgrps/team-group/wfs/"
+
+	t.Run("Clone_To_Another_Org", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{templateWorkflow, `{"msg": {}}`, `{"msg": {}}`,
+			`{"msg": "Workflow new-workflow created", "data": {"ResourceName": "new-workflow"}}`}}
+		stdout, _, err := runWorkflow(t, sequence, "clone", "--to-org", "other-org", "--to-workflow-group", "team-group",
+			"--name", "new-workflow", "--patch-payload", `{"Description": "team"}`)
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, "Workflow not-an-actual-workflow cloned to new-workflow successfully.\n")
+		assert.Len(t, sequence.paths, 4)
+		assert.True(t, strings.HasPrefix(sequence.paths[1], "GET /api/v1/orgs/other-org/integrations/aws-prod"), sequence.paths[1])
+		assert.True(t, strings.HasPrefix(sequence.paths[2], "GET /api/v1/orgs/other-org/runnergroups/team-runners"), sequence.paths[2])
+		assert.Equal(t, createPath, sequence.paths[3])
+		assert.Contains(t, sequence.sent[3], `"ResourceName":"new-workflow"`)
+		assert.Contains(t, sequence.sent[3], `"Description":"team"`)
+		assert.NotContains(t, sequence.sent[3], "ResourceId")
+	})
+
+	t.Run("Missing_Integration", func(t *testing.T) {
+		sequence := &sequenceTransport{
+			bodies:   []string{templateWorkflow, `{"msg": "Integration not found"}`, `{"msg": {}}`},
+			statuses: []int{0, http.StatusNotFound},
+		}
+		_, stderr, err := runWorkflow(t, sequence, "clone", "--to-org", "other-org", "--to-workflow-group", "team-group")
+		assert.Equal(t, 5, clierrors.ExitCode(err), err)
+		assert.Contains(t, stderr, ">> [ERROR] integration aws-prod used by $.DeploymentPlatformConfig[0].config.integrationId does not exist in org other-org")
+		assert.NotContains(t, sequence.paths, createPath)
+	})
+
+	t.Run("Same_Target", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{templateWorkflow}}
+		_, _, err := runWorkflow(t, sequence, "clone")
+		assert.Equal(t, 2, clierrors.ExitCode(err), err)
+		assert.Empty(t, sequence.paths)
+	})
+
+	t.Run("Patch_Not_An_Object", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{templateWorkflow}}
+		_, _, err := runWorkflow(t, sequence, "clone", "--name", "copy", "--patch-payload", "[1]")
+		assert.Equal(t, 5, clierrors.ExitCode(err), err)
+		assert.Empty(t, sequence.paths)
+	})
+
+	t.Run("Copy_State", func(t *testing.T) {
+		t.Setenv("SG_CONFIG_FILE", filepath.Join(t.TempDir(), "config.yaml"))
+		t.Cleanup(func() { transport.SetClient(nil) })
+		state := &sequenceTransport{bodies: []string{`{"version": 4}`, `{"msg": "https://storage.example.com/upload.tfstate"}`, ``}}
+		transport.SetClient(&http.Client{Transport: state})
+		sequence := &sequenceTransport{bodies: []string{templateWorkflow, `{"msg": {}}`, `{"msg": {}}`,
+			`{"msg": "Workflow copy created", "data": {"ResourceName": "copy"}}`,
+			`{"msg": "Artifacts", "data": {"artifacts": {
+				"orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/artifacts/tfstate.json": {"url": "https://storage.example.com/download.tfstate"}
+			}}}`,
+		}}
+		stdout, _, err := runWorkflow(t, sequence, "clone", "--name", "copy", "--copy-state")
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, ">> State file copied successfully.\n")
+		assert.Equal(t, "GET /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/listall_artifacts", sequence.paths[4])
+		assert.Equal(t, []string{
+			"GET /download.tfstate",
+			"GET /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/copy/tfstate_upload_url",
+			"PUT /upload.tfstate",
+		}, state.paths)
+		assert.Equal(t, `{"version": 4}`, state.sent[2])
+	})
+}
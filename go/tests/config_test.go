@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigProfiles(t *testing.T) {
+	t.Setenv("SG_CONFIG_FILE", filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv("SG_PROFILE", "")
+	for _, env := range []string{"SG_API_TOKEN", "SG_BASE_URL", "SG_DASHBOARD_URL", "SG_ORG", "SG_WORKFLOW_GROUP"} {
+		t.Setenv(env, "")
+	}
+
+	file, err := config.Load()
+	assert.NoError(t, err)
+	assert.NoError(t, file.Profile("qa").Set("api-url", "https://api.qa.stackguardian.io/"))
+	assert.NoError(t, file.Profile("qa").Set("org", "qa-org"))
+	assert.Error(t, file.Profile("qa").Set("not-a-key", "value"))
+	file.CurrentProfile = "qa"
+	assert.NoError(t, file.Save())
+
+	t.Run("Current_Profile_From_File", func(t *testing.T) {
+		settings, err := config.Resolve("")
+		assert.NoError(t, err)
+		assert.Equal(t, "qa", settings.ProfileName)
+		assert.True(t, settings.ProfileExists)
+		assert.Equal(t, "https://api.qa.stackguardian.io", settings.APIURL)
+		assert.Equal(t, config.DefaultDashboardURL, settings.DashboardURL)
+		assert.Equal(t, "qa-org", settings.Org)
+	})
+
+	t.Run("Profile_Flag_Overrides_Current_Profile", func(t *testing.T) {
+		settings, err := config.Resolve("prod")
+		assert.NoError(t, err)
+		assert.Equal(t, "prod", settings.ProfileName)
+		assert.False(t, settings.ProfileExists)
+		assert.Equal(t, config.DefaultAPIURL, settings.APIURL)
+	})
+
+	t.Run("Env_Overrides_File", func(t *testing.T) {
+		t.Setenv("SG_ORG", "env-org")
+		t.Setenv("SG_API_TOKEN", "sgu_from_env")
+		settings, err := config.Resolve("")
+		assert.NoError(t, err)
+		assert.Equal(t, "env-org", settings.Org)
+		assert.Equal(t, "sgu_from_env", settings.APIToken)
+	})
+}
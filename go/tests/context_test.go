@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	workflowcmd "github.com/StackGuardian/sg-cli/cmd/workflow"
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	option "github.com/StackGuardian/sg-sdk-go/option"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingTransport answers every request with an empty workflow list and records the request URLs
+type recordingTransport struct {
+	urls []string
+}
+
+func (r *recordingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	r.urls = append(r.urls, request.URL.Path)
+	return &http.Response{
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{"msg": []}`))),
+		StatusCode: http.StatusOK,
+	}, nil
+}
+
+func TestWorkflowUsesCurrentContext(t *testing.T) {
+	t.Setenv("SG_CONFIG_FILE", filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv("SG_ORG", "")
+	t.Setenv("SG_WORKFLOW_GROUP", "")
+	config.SetCurrent(nil)
+	defer config.SetCurrent(nil)
+
+	assert.NoError(t, config.SaveContext(config.DefaultProfile, &config.Context{
+		Org:           "context-org",
+		WorkflowGroup: "context-workflow-group",
+	}))
+
+	cases := []struct {
+		name        string
+		args        []string
+		expectedURL string
+	}{
+		{
+			name:        "Context_Only",
+			args:        []string{"list"},
+			expectedURL: "/api/v1/orgs/context-org/wfgrps/context-workflow-group/wfs/listall/",
+		},
+		{
+			name:        "Flag_Overrides_Context",
+			args:        []string{"list", "--workflow-group", "flag-workflow-group"},
+			expectedURL: "/api/v1/orgs/context-org/wfgrps/flag-workflow-group/wfs/listall/",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			transport := &recordingTransport{}
+			c := client.NewClient(option.WithHTTPClient(&http.Client{Transport: transport}))
+			cmd := workflowcmd.NewWorkflowCmd(c)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.NoError(t, cmd.Execute())
+			assert.Equal(t, []string{tc.expectedURL}, transport.urls)
+		})
+	}
+
+	t.Run("Missing_Context", func(t *testing.T) {
+		assert.NoError(t, config.ClearContext(config.DefaultProfile))
+		transport := &recordingTransport{}
+		c := client.NewClient(option.WithHTTPClient(&http.Client{Transport: transport}))
+		cmd := workflowcmd.NewWorkflowCmd(c)
+		cmd.SetArgs([]string{"list"})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		err := cmd.Execute()
+		assert.ErrorContains(t, err, errRequiredFlag)
+		assert.Empty(t, transport.urls)
+	})
+}
@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	opencmd "github.com/StackGuardian/sg-cli/cmd/open"
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/StackGuardian/sg-cli/dashboard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboardLinks(t *testing.T) {
+	links := dashboard.New("https://dashboard.example.com/orchestrator/")
+
+	assert.Equal(t, "https://dashboard.example.com/orchestrator/orgs/my-org/wfgrps/my-wfgrp/wfs/my-wf?tab=runs",
+		links.WorkflowRuns("my-org", "my-wfgrp", "my-wf"))
+	assert.Equal(t, "https://dashboard.example.com/orchestrator/orgs/my-org/wfgrps/my-wfgrp/wfs/my-wf/wfruns/run-1",
+		links.WorkflowRun("my-org", "my-wfgrp", "my-wf", "run-1"))
+	assert.Equal(t, "https://dashboard.example.com/orchestrator/orgs/my-org/wfgrps/my-wfgrp/stacks/my-stack/stackruns/run-1",
+		links.StackRun("my-org", "my-wfgrp", "my-stack", "run-1"))
+	assert.Equal(t, "https://dashboard.example.com/orchestrator/orgs/my-org/wfgrps/my-wfgrp/stacks/my-stack/wfs/my-wf/wfruns/run-1",
+		links.StackWorkflowRun("my-org", "my-wfgrp", "my-stack", "my-wf", "run-1"))
+	assert.Equal(t, "https://dashboard.example.com/orchestrator/orgs/my-org/settings?tab=api_key",
+		links.APIKeySettings("my-org"))
+	assert.Equal(t, "https://dashboard.example.com/orchestrator/orgs/my-org/wfgrps/group%20with%20spaces",
+		links.WorkflowGroup("my-org", "group with spaces"))
+
+	t.Run("Open_Uses_SG_DASHBOARD_URL", func(t *testing.T) {
+		t.Setenv("SG_CONFIG_FILE", filepath.Join(t.TempDir(), "config.yaml"))
+		t.Setenv("SG_DASHBOARD_URL", "https://sg.internal.example.com")
+		t.Setenv("SG_ORG", "env-org")
+		t.Setenv("SG_WORKFLOW_GROUP", "")
+		config.SetCurrent(nil)
+		defer config.SetCurrent(nil)
+
+		b := bytes.NewBufferString("")
+		cmd := opencmd.NewOpenCmd()
+		cmd.SetArgs([]string{"stack", "my-stack", "--workflow-group", "my-wfgrp", "--no-browser"})
+		cmd.SetOut(b)
+		cmd.SetErr(&bytes.Buffer{})
+		assert.NoError(t, cmd.Execute())
+		assert.Equal(t, "https://sg.internal.example.com/orgs/env-org/wfgrps/my-wfgrp/stacks/my-stack?tab=runs\n", b.String())
+	})
+}
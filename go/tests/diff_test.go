@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/stretchr/testify/assert"
+)
+
+const liveWorkflow = `{"msg": {
+    "ResourceName": "not-an-actual-workflow",
+    "ResourceId": "/wfs/not-an-actual-workflow",
+    "LatestWfrunStatus": "COMPLETED",
+    "Description": "demo",
+    "UserJobCPU": 1024,
+    "EnvironmentVariables": [
+        {"kind": "PLAIN_TEXT", "config": {"varName": "FOO", "textValue": "a"}},
+        {"kind": "PLAIN_TEXT", "config": {"varName": "API_TOKEN", "textValue": "old-token"}}
+    ]
+}}`
+
+func TestDiffWorkflow(t *testing.T) {
+	write := func(t *testing.T, payload string) string {
+		path := filepath.Join(t.TempDir(), "workflow.json")
+		if err := os.WriteFile(path, []byte(payload), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	drifted := `{"Description": "demo", "EnvironmentVariables": [
+		{"kind": "PLAIN_TEXT", "config": {"varName": "API_TOKEN", "textValue": "new-token"}},
+		{"kind": "PLAIN_TEXT", "config": {"varName": "FOO", "textValue": "b"}},
+		{"kind": "PLAIN_TEXT", "config": {"varName": "DB_PASSWORD", "textValue": "hunter2"}}
+	]}`
+
+	t.Run("Drift", func(t *testing.T) {
+		payload := write(t, drifted)
+		sequence := &sequenceTransport{bodies: []string{liveWorkflow}}
+		stdout, stderr, err := runWorkflow(t, sequence, "diff", payload)
+		assert.Equal(t, 1, clierrors.ExitCode(err), err)
+		assert.Equal(t, clierrors.Drift, clierrors.From(err).Category)
+		assert.Equal(t, ">> Workflow not-an-actual-workflow differs from "+payload+`:
+~ $.EnvironmentVariables[varName=API_TOKEN].config.textValue: "(sensitive)" -> "(sensitive)"
++ $.EnvironmentVariables[varName=DB_PASSWORD]: {"config":{"textValue":"(sensitive)","varName":"DB_PASSWORD"},"kind":"PLAIN_TEXT"}
+~ $.EnvironmentVariables[varName=FOO].config.textValue: "a" -> "b"
+`, stdout)
+		assert.NotContains(t, stdout+stderr, "token\"")
+		assert.NotContains(t, stdout+stderr, "hunter2")
+	})
+
+	t.Run("No_Drift", func(t *testing.T) {
+		payload := write(t, `{"EnvironmentVariables": [
+			{"config": {"textValue": "old-token", "varName": "API_TOKEN"}, "kind": "PLAIN_TEXT"},
+			{"config": {"textValue": "a", "varName": "FOO"}, "kind": "PLAIN_TEXT"}
+		], "Description": "demo", "ResourceName": "not-an-actual-workflow"}`)
+		sequence := &sequenceTransport{bodies: []string{liveWorkflow}}
+		stdout, _, err := runWorkflow(t, sequence, "diff", payload)
+		assert.NoError(t, err)
+		assert.Equal(t, ">> Workflow not-an-actual-workflow matches "+payload+".\n", stdout)
+	})
+
+	t.Run("Color", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{liveWorkflow}}
+		stdout, _, err := runWorkflow(t, sequence, "diff", write(t, drifted), "--color", "always")
+		assert.Equal(t, 1, clierrors.ExitCode(err), err)
+		assert.Contains(t, stdout, "\x1b[32m+ $.EnvironmentVariables[varName=DB_PASSWORD]")
+		assert.Contains(t, stdout, "\x1b[33m~ $.EnvironmentVariables[varName=FOO].config.textValue: \"a\" -> \"b\"\x1b[0m")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{liveWorkflow}}
+		stdout, _, err := runWorkflow(t, sequence, "diff", write(t, drifted), "-o", "json")
+		assert.Equal(t, 1, clierrors.ExitCode(err), err)
+		var result struct {
+			Workflow string                   `json:"workflow"`
+			Msg      []map[string]interface{} `json:"msg"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(stdout), &result))
+		assert.Equal(t, "not-an-actual-workflow", result.Workflow)
+		assert.Len(t, result.Msg, 3)
+		assert.Equal(t, map[string]interface{}{"path": "$.EnvironmentVariables[varName=FOO].config.textValue", "kind": "changed",
+			"before": "a", "after": "b"}, result.Msg[2])
+	})
+
+	t.Run("Missing_Payload", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{liveWorkflow}}
+		_, _, err := runWorkflow(t, sequence, "diff", filepath.Join(t.TempDir(), "missing.json"))
+		assert.Equal(t, 2, clierrors.ExitCode(err), err)
+		assert.Empty(t, sequence.paths)
+	})
+
+	t.Run("Invalid_Payload", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{liveWorkflow}}
+		_, _, err := runWorkflow(t, sequence, "diff", write(t, `{"Description": `))
+		assert.Equal(t, 5, clierrors.ExitCode(err), err)
+		assert.Empty(t, sequence.paths)
+	})
+
+	t.Run("Unknown_Field", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{liveWorkflow}}
+		_, _, err := runWorkflow(t, sequence, "diff", write(t, `{"Descripton": "typo"}`))
+		assert.Equal(t, 5, clierrors.ExitCode(err), err)
+		assert.Empty(t, sequence.paths)
+	})
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	workflowcmd "github.com/StackGuardian/sg-cli/cmd/workflow"
+	"github.com/StackGuardian/sg-cli/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+const readWorkflow = `{"msg": {
+    "ResourceName": "not-an-actual-workflow",
+    "ResourceId": "/wfs/not-an-actual-workflow",
+    "SubResourceId": "/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow",
+    "OrgId": "/orgs/not-an-actual-org",
+    "CreatedAt": 1729823854332,
+    "ModifiedAt": 1729823854332,
+    "LatestWfrunStatus": "COMPLETED",
+    "Authors": ["dummy@dummy.com"],
+    "Description": "test desc",
+    "WfType": "TERRAFORM",
+    "UserJobCPU": 1024,
+    "TerraformConfig": {"managedTerraformState": true},
+    "TfStateCleaned": {"resources": []},
+    "Approvers": null
+}}`
+
+func TestExportWorkflow(t *testing.T) {
+	t.Run("Single", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{readWorkflow}}
+		stdout, _, err := runWorkflow(t, sequence, "export")
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+			"ResourceName": "not-an-actual-workflow",
+			"Description": "test desc",
+			"WfType": "TERRAFORM",
+			"UserJobCPU": 1024,
+			"TerraformConfig": {"managedTerraformState": true}
+		}`, stdout)
+	})
+
+	t.Run("All_With_State", func(t *testing.T) {
+		t.Setenv("SG_CONFIG_FILE", filepath.Join(t.TempDir(), "config.yaml"))
+		t.Cleanup(func() { transport.SetClient(nil) })
+		dir := t.TempDir()
+		sequence := &sequenceTransport{bodies: []string{
+			`{"msg": [{"ResourceName": "first"}], "lastevaluatedkey": "next"}`,
+			`{"msg": {"ResourceName": "first", "ResourceId": "/wfs/first"}}`,
+			`{"msg": "Artifacts", "data": {"artifacts": {
+				"orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/first/wfruns/run-1/artifacts/tfstate.json": {"url": "https://storage.example.com/run-1.tfstate"},
+				"orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/first/artifacts/tfstate.json": {"url": "https://storage.example.com/first.tfstate"}
+			}}}`,
+			`{"msg": [{"ResourceName": "second"}], "lastevaluatedkey": ""}`,
+			`{"msg": {"ResourceName": "second", "ResourceId": "/wfs/second"}}`,
+			`{"msg": "Artifacts", "data": {"artifacts": {}}}`,
+		}}
+		state := &sequenceTransport{bodies: []string{`{"version": 4}`}}
+		transport.SetClient(&http.Client{Transport: state})
+		file := filepath.Join(dir, "workflows.json")
+
+		_, _, err := runCmd(t, workflowcmd.NewWorkflowCmd, sequence, "",
+			[]string{"export", "--all", "--file", file, "--state-dir", filepath.Join(dir, "state")}, testFlags...)
+		assert.NoError(t, err)
+		assert.Contains(t, sequence.queries[3], "lastevaluatedkey=next")
+		// The state is the tfstate.json artifact of the workflow, not the one of a run
+		assert.Equal(t, "GET /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/first/listall_artifacts", sequence.paths[2])
+		assert.Equal(t, []string{"GET /first.tfstate"}, state.paths)
+
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		var exported []map[string]interface{}
+		assert.NoError(t, json.Unmarshal(content, &exported))
+		assert.Equal(t, []map[string]interface{}{
+			{"ResourceName": "first", "CLIConfiguration": map[string]interface{}{
+				"WorkflowGroup":   map[string]interface{}{"name": "not-an-actual-workflow-group"},
+				"TfStateFilePath": filepath.Join(dir, "state", "first.tfstate"),
+			}},
+			{"ResourceName": "second", "CLIConfiguration": map[string]interface{}{
+				"WorkflowGroup": map[string]interface{}{"name": "not-an-actual-workflow-group"},
+			}},
+		}, exported)
+		downloaded, err := os.ReadFile(filepath.Join(dir, "state", "first.tfstate"))
+		assert.NoError(t, err)
+		assert.Equal(t, `{"version": 4}`, string(downloaded))
+		// The payloads and the state may hold secrets
+		for _, path := range []string{file, filepath.Join(dir, "state", "first.tfstate")} {
+			info, err := os.Stat(path)
+			if assert.NoError(t, err) {
+				assert.Equal(t, os.FileMode(0o600), info.Mode().Perm(), path)
+			}
+		}
+	})
+
+	t.Run("Workflow_And_All", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{readWorkflow}}
+		_, _, err := runWorkflow(t, sequence, "export", "--all")
+		assert.Equal(t, 2, clierrors.ExitCode(err), err)
+		assert.Empty(t, sequence.paths)
+	})
+}
@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	stackcmd "github.com/StackGuardian/sg-cli/cmd/stack"
+	"github.com/StackGuardian/sg-cli/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+func workflowRuns(status string) string {
+	return `{"msg": [{"ResourceName": "previous-run", "LatestStatus": "` + status + `", "CreatedAt": 1729823822294}], "lastevaluatedkey": ""}`
+}
+
+func workflowStatus(status string) string {
+	return `{"msg": {"ResourceName": "not-an-actual-workflow", "LatestWfrunStatus": "` + status + `"}}`
+}
+
+func stackRuns(status string) string {
+	return `{"msg": [{"ResourceName": "not-an-actual-stack-run", "StackRunId": "/stackruns/not-an-actual-stack-run", "LatestStatus": "` + status + `"}], "lastevaluatedkey": ""}`
+}
+
+func TestGuardWorkflowRun(t *testing.T) {
+	const readPath = "GET /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow"
+	const runsPath = "GET /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/listall/"
+	const createPath = "POST /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/"
+
+	cases := []struct {
+		name             string
+		args             []string
+		bodies           []string
+		expectedExitCode int
+		expectedPaths    []string
+		expectedOutput   string
+	}{
+		{
+			name:             "IfIdle_Busy",
+			args:             []string{"apply", "--if-idle"},
+			bodies:           []string{workflowStatus("RUNNING"), workflowRuns("RUNNING")},
+			expectedExitCode: 13,
+			expectedPaths:    []string{readPath, runsPath},
+		},
+		{
+			name:             "IfIdle_Idle",
+			args:             []string{"destroy", "--if-idle"},
+			bodies:           []string{workflowStatus("COMPLETED"), createdRun},
+			expectedExitCode: 0,
+			expectedPaths:    []string{readPath, createPath},
+		},
+		{
+			name: "Queue",
+			args: []string{"apply", "--queue", "--poll-interval", "1ms"},
+			bodies: []string{workflowStatus("QUEUED"), workflowRuns("QUEUED"), workflowStatus("RUNNING"), workflowRuns("RUNNING"),
+				workflowStatus("RUNNING"), workflowRuns("RUNNING"), workflowStatus("ERRORED"), createdRun},
+			expectedExitCode: 0,
+			expectedPaths:    []string{readPath, runsPath, readPath, runsPath, readPath, runsPath, readPath, createPath},
+			expectedOutput:   "] Workflow not-an-actual-workflow is busy, waiting for Workflow run previous-run (RUNNING) to finish\n",
+		},
+		{
+			name: "Supersede",
+			args: []string{"apply", "--supersede", "--poll-interval", "1ms"},
+			bodies: []string{workflowStatus("APPROVAL_REQUIRED"), workflowRuns("APPROVAL_REQUIRED"), `{"msg": "Workflow Run cancelled"}`,
+				workflowStatus("CANCELLED"), createdRun},
+			expectedExitCode: 0,
+			expectedPaths: []string{readPath, runsPath,
+				"PATCH /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/previous-run/cancel/",
+				readPath, createPath},
+			expectedOutput: ">> Cancelling Workflow run previous-run (APPROVAL_REQUIRED)\n",
+		},
+		{
+			name: "Latest_Page_Only",
+			args: []string{"apply", "--if-idle"},
+			bodies: []string{workflowStatus("RUNNING"),
+				`{"msg": [{"ResourceName": "latest-run", "LatestStatus": "COMPLETED"}], "lastevaluatedkey": "page-2"}`, createdRun},
+			expectedExitCode: 0,
+			expectedPaths:    []string{readPath, runsPath, createPath},
+		},
+		{
+			name:             "Flags_Conflict",
+			args:             []string{"apply", "--queue", "--supersede"},
+			bodies:           []string{createdRun},
+			expectedExitCode: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sequence := &sequenceTransport{bodies: tc.bodies}
+			stdout, _, err := runWorkflow(t, sequence, tc.args...)
+
+			assert.Equal(t, tc.expectedExitCode, clierrors.ExitCode(err), err)
+			assert.Equal(t, tc.expectedPaths, sequence.paths)
+			assert.Contains(t, stdout, tc.expectedOutput)
+		})
+	}
+}
+
+func TestGuardStackRun(t *testing.T) {
+	t.Setenv("SG_CONFIG_FILE", filepath.Join(t.TempDir(), "config.yaml"))
+	t.Cleanup(func() { transport.SetClient(nil) })
+	const runsPath = "GET /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/stacks/not-an-actual-stack/stackruns/listall/"
+
+	run := func(t *testing.T, sequence *sequenceTransport, args ...string) (string, error) {
+		t.Helper()
+		stdout, _, err := runCmd(t, stackcmd.NewStackCmd, sequence, "", args,
+			append(testFlags, "--stack-id", "not-an-actual-stack", "--poll-interval", "1ms")...)
+		return stdout, err
+	}
+
+	t.Run("IfIdle_Busy", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{stackRuns("RUNNING")}}
+		_, err := run(t, sequence, "destroy", "--if-idle")
+		assert.Equal(t, 13, clierrors.ExitCode(err), err)
+		assert.Equal(t, []string{runsPath}, sequence.paths)
+	})
+
+	t.Run("Latest_Page_Only", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{
+			`{"msg": [{"ResourceName": "latest-run", "StackRunId": "/stackruns/latest-run", "LatestStatus": "COMPLETED"}], "lastevaluatedkey": "page-2"}`,
+			createdStackRun,
+		}}
+		_, err := run(t, sequence, "apply", "--if-idle")
+		assert.Equal(t, 0, clierrors.ExitCode(err), err)
+		assert.Equal(t, []string{runsPath, "POST /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/stacks/not-an-actual-stack/stackruns/"}, sequence.paths)
+	})
+
+	t.Run("Supersede", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{stackRuns("RUNNING"), stackRunStatus("RUNNING", "COMPLETED", "RUNNING"),
+			stackRuns("CANCELLED"), createdStackRun}}
+		cancel := &sequenceTransport{bodies: []string{`{"msg": "Workflow Run cancelled"}`}}
+		transport.SetClient(&http.Client{Transport: cancel})
+		out, err := run(t, sequence, "apply", "--supersede")
+		assert.Equal(t, 0, clierrors.ExitCode(err), err)
+		assert.Contains(t, out, ">> Cancelling Stack run not-an-actual-stack-run (RUNNING)\n")
+		assert.Equal(t, []string{"PATCH /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/stacks/not-an-actual-stack/wfs/database/wfruns/run-database/cancel/"}, cancel.paths)
+		assert.Len(t, sequence.paths, 4)
+		assert.Equal(t, "POST /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/stacks/not-an-actual-stack/stackruns/", sequence.paths[3])
+	})
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	workflowcmd "github.com/StackGuardian/sg-cli/cmd/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+const workflowsFirstPage = `{"lastevaluatedkey": "page-2", "msg": [
+    {"ResourceName": "team-a-network", "WfType": "TERRAFORM", "LatestWfrunStatus": "COMPLETED", "Tags": ["team-a", "prod"], "ModifiedAt": 1729823854332},
+    {"ResourceName": "team-b-network", "WfType": "OPENTOFU", "LatestWfrunStatus": "ERRORED", "Tags": ["team-b"], "ModifiedAt": 1729254579019}
+]}`
+
+const workflowsLastPage = `{"lastevaluatedkey": "", "msg": [
+    {"ResourceName": "team-a-database", "WfType": "TERRAFORM", "LatestWfrunStatus": "ERRORED", "Tags": ["team-a"], "ModifiedAt": 1729900000000},
+    {"ResourceName": "shared-dns", "WfType": "CUSTOM", "LatestWfrunStatus": "UNTRACKED", "ModifiedAt": 1729000000000}
+]}`
+
+// runList runs workflow list in the test org and workflow group
+func runList(t *testing.T, sequence *sequenceTransport, args ...string) (string, string, error) {
+	t.Helper()
+	return runCmd(t, workflowcmd.NewWorkflowCmd, sequence, "", append([]string{"list"}, args...), testFlags...)
+}
+
+func TestListWorkflowsPages(t *testing.T) {
+	t.Run("All_Pages", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{workflowsFirstPage, workflowsLastPage}}
+		stdout, _, err := runList(t, sequence, "-o", "name")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"", "lastevaluatedkey=page-2"}, sequence.queries)
+		assert.Equal(t, "team-a-network\nteam-b-network\nteam-a-database\nshared-dns\n", stdout)
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{workflowsFirstPage, workflowsLastPage}}
+		stdout, _, err := runList(t, sequence, "--tag", "team-a", "--type", "terraform", "--name", "team-*", "--status", "errored,completed", "-o", "name")
+		assert.NoError(t, err)
+		assert.Equal(t, "team-a-network\nteam-a-database\n", stdout)
+
+		sequence = &sequenceTransport{bodies: []string{workflowsFirstPage, workflowsLastPage}}
+		stdout, _, err = runList(t, sequence, "--name-regex", "network$", "-o", "name")
+		assert.NoError(t, err)
+		assert.Equal(t, "team-a-network\nteam-b-network\n", stdout)
+	})
+
+	t.Run("Sort", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{workflowsFirstPage, workflowsLastPage}}
+		stdout, _, err := runList(t, sequence, "--sort-by", "modified", "--limit", "2", "-o", "name")
+		assert.NoError(t, err)
+		assert.Equal(t, "team-a-database\nteam-a-network\n", stdout)
+		assert.Len(t, sequence.paths, 2)
+
+		sequence = &sequenceTransport{bodies: []string{workflowsFirstPage, workflowsLastPage}}
+		stdout, _, err = runList(t, sequence, "--sort-by", "status", "-o", "name")
+		assert.NoError(t, err)
+		assert.Equal(t, "team-a-network\nteam-a-database\nteam-b-network\nshared-dns\n", stdout)
+	})
+
+	t.Run("Limit_And_Page_Token", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{workflowsFirstPage, workflowsLastPage}}
+		stdout, stderr, err := runList(t, sequence, "--limit", "2", "-o", "name")
+		assert.NoError(t, err)
+		assert.Equal(t, "team-a-network\nteam-b-network\n", stdout)
+		assert.Len(t, sequence.paths, 1)
+		assert.Contains(t, stderr, "--page-token page-2 to continue")
+
+		sequence = &sequenceTransport{bodies: []string{workflowsLastPage}}
+		stdout, _, err = runList(t, sequence, "--page-token", "page-2", "-o", "name")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"lastevaluatedkey=page-2"}, sequence.queries)
+		assert.Equal(t, "team-a-database\nshared-dns\n", stdout)
+	})
+
+	t.Run("Limit_Inside_A_Page", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{workflowsFirstPage}}
+		stdout, stderr, err := runList(t, sequence, "--limit", "1", "-o", "name")
+		assert.NoError(t, err)
+		assert.Equal(t, "team-a-network\n", stdout)
+		token := regexp.MustCompile(`--page-token (\S+) to continue`).FindStringSubmatch(stderr)
+		if !assert.Len(t, token, 2, stderr) {
+			return
+		}
+
+		// The listing continues with the next workflow of the same page
+		sequence = &sequenceTransport{bodies: []string{workflowsFirstPage, workflowsLastPage}}
+		stdout, _, err = runList(t, sequence, "--page-token", token[1], "--limit", "2", "-o", "name")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"", "lastevaluatedkey=page-2"}, sequence.queries)
+		assert.Equal(t, "team-b-network\nteam-a-database\n", stdout)
+	})
+
+	t.Run("Runner_Type", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{
+			`{"lastevaluatedkey": "", "msg": [
+				{"ResourceName": "on-prem", "RunnerConstraints": {"type": "private", "names": ["rg"]}},
+				{"ResourceName": "cloud"}
+			]}`,
+			`{"msg": {"ResourceName": "cloud", "RunnerConstraints": {"type": "shared"}}}`,
+		}}
+		stdout, _, err := runList(t, sequence, "--runner-type", "private", "-o", "name")
+		assert.NoError(t, err)
+		assert.Equal(t, "on-prem\n", stdout)
+		// Only the workflow without runner constraints in the list is read
+		assert.Equal(t, "GET /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/cloud", sequence.paths[1])
+		assert.Len(t, sequence.paths, 2)
+	})
+
+	t.Run("Invalid_Sort", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{workflowsLastPage}}
+		_, _, err := runList(t, sequence, "--sort-by", "size")
+		assert.Equal(t, 2, clierrors.ExitCode(err), err)
+		assert.Empty(t, sequence.paths)
+	})
+}
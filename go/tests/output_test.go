@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	artifactscmd "github.com/StackGuardian/sg-cli/cmd/artifacts"
+	workflowcmd "github.com/StackGuardian/sg-cli/cmd/workflow"
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	option "github.com/StackGuardian/sg-sdk-go/option"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+const workflowListResponse = `{
+    "msg": [
+        {
+            "ResourceName": "not-an-actual-workflow",
+            "WfType": "TERRAFORM",
+            "LatestWfrunStatus": "COMPLETED",
+            "Description": "test desc",
+            "ResourceId": "/wfs/not-an-actual-workflow",
+            "CreatedAt": 1729823854332
+        },
+        {
+            "ResourceName": "not-an-actual-workflow-2",
+            "WfType": "CUSTOM",
+            "LatestWfrunStatus": "ERRORED",
+            "Description": "second\tdesc",
+            "ResourceId": "/wfs/not-an-actual-workflow-2",
+            "CreatedAt": 1729254579019
+        }
+    ]
+}`
+
+const artifactsListResponse = `{
+    "msg": "Outputs retrieved",
+    "data": {
+        "artifacts": {
+            "artifacts/tfstate.json": {
+                "url": "https://bucket.example.com/artifacts/tfstate.json",
+                "lastModified": "2024-10-17 15:54:00+00:00",
+                "size": 6548
+            }
+        }
+    }
+}`
+
+// runWithOutput runs a group command with the global output flags, as the root command adds them
+func runWithOutput(t *testing.T, newCmd func(c *client.Client) *cobra.Command, response string, args ...string) (string, string, error) {
+	t.Helper()
+	transport := &statusTransport{statusCode: http.StatusOK, body: response}
+	cmd := newCmd(client.NewClient(option.WithHTTPClient(&http.Client{Transport: transport})))
+	output.AddFlags(cmd)
+	cmd.SetArgs(args)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	err := cmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestOutputFormats(t *testing.T) {
+	list := []string{"list", "--org", "not-an-actual-org", "--workflow-group", "not-an-actual-workflow-group"}
+
+	cases := []struct {
+		name     string
+		format   string
+		expected string
+	}{
+		{"Table", "table", "" +
+			"NAME                       TYPE        STATUS      TAGS   MODIFIED   DESCRIPTION\n" +
+			"not-an-actual-workflow     TERRAFORM   COMPLETED                     test desc\n" +
+			"not-an-actual-workflow-2   CUSTOM      ERRORED                       second desc\n"},
+		{"Wide", "wide", "" +
+			"NAME                       TYPE        STATUS      TAGS   MODIFIED   DESCRIPTION   REPO   ID                              AUTHORS   CREATED\n" +
+			"not-an-actual-workflow     TERRAFORM   COMPLETED                     test desc            /wfs/not-an-actual-workflow               2024-10-25T02:37:34Z\n" +
+			"not-an-actual-workflow-2   CUSTOM      ERRORED                       second desc          /wfs/not-an-actual-workflow-2             2024-10-18T12:29:39Z\n"},
+		{"CSV", "csv", "" +
+			"NAME,TYPE,STATUS,TAGS,MODIFIED,DESCRIPTION,REPO,ID,AUTHORS,CREATED\n" +
+			"not-an-actual-workflow,TERRAFORM,COMPLETED,,,test desc,,/wfs/not-an-actual-workflow,,2024-10-25T02:37:34Z\n" +
+			"not-an-actual-workflow-2,CUSTOM,ERRORED,,,second\tdesc,,/wfs/not-an-actual-workflow-2,,2024-10-18T12:29:39Z\n"},
+		{"Name", "name", "not-an-actual-workflow\nnot-an-actual-workflow-2\n"},
+		{"JSONL", "jsonl", "" +
+			`{"CreatedAt":1729823854332,"Description":"test desc","LatestWfrunStatus":"COMPLETED","ResourceId":"/wfs/not-an-actual-workflow","ResourceName":"not-an-actual-workflow","WfType":"TERRAFORM"}` + "\n" +
+			`{"CreatedAt":1729254579019,"Description":"second\tdesc","LatestWfrunStatus":"ERRORED","ResourceId":"/wfs/not-an-actual-workflow-2","ResourceName":"not-an-actual-workflow-2","WfType":"CUSTOM"}` + "\n"},
+		{"YAML", "yaml", "" +
+			"msg:\n" +
+			"    - CreatedAt: 1729823854332\n" +
+			"      Description: test desc\n" +
+			"      LatestWfrunStatus: COMPLETED\n" +
+			"      ResourceId: /wfs/not-an-actual-workflow\n" +
+			"      ResourceName: not-an-actual-workflow\n" +
+			"      WfType: TERRAFORM\n" +
+			"    - CreatedAt: 1729254579019\n" +
+			"      Description: \"second\\tdesc\"\n" +
+			"      LatestWfrunStatus: ERRORED\n" +
+			"      ResourceId: /wfs/not-an-actual-workflow-2\n" +
+			"      ResourceName: not-an-actual-workflow-2\n" +
+			"      WfType: CUSTOM\n"},
+		{"JSONPath", "jsonpath={.msg[*].ResourceName}", "not-an-actual-workflow not-an-actual-workflow-2\n"},
+		{"JSONPath_Index", "jsonpath=last: {.msg[-1].WfType}", "last: CUSTOM\n"},
+		{"Go_Template", `go-template={{range .msg}}{{.ResourceName}}={{.LatestWfrunStatus}};{{end}}`, "not-an-actual-workflow=COMPLETED;not-an-actual-workflow-2=ERRORED;"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stdout, _, err := runWithOutput(t, workflowcmd.NewWorkflowCmd, workflowListResponse, append(list, "-o", tc.format)...)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, stdout)
+		})
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		stdout, _, err := runWithOutput(t, workflowcmd.NewWorkflowCmd, `{"msg":[{"ResourceName":"not-an-actual-workflow"}]}`, append(list, "-o", "json")...)
+		assert.NoError(t, err)
+		assert.Equal(t, "{\n  \"msg\": [\n    {\n      \"ResourceName\": \"not-an-actual-workflow\"\n    }\n  ]\n}\n", stdout)
+	})
+
+	t.Run("Deprecated_Output_JSON", func(t *testing.T) {
+		stdout, stderr, err := runWithOutput(t, workflowcmd.NewWorkflowCmd, `{"msg":[{"ResourceName":"not-an-actual-workflow"}]}`, append(list, "--output-json")...)
+		assert.NoError(t, err)
+		// cobra prints the deprecation warning to the output stream, which is stderr unless it was redirected
+		assert.Contains(t, stdout, "Flag --output-json has been deprecated, use --output json instead\n{\n  \"msg\": [")
+		assert.NotContains(t, stderr, "Workflow")
+	})
+
+	t.Run("Messages_Go_To_Stderr", func(t *testing.T) {
+		stdout, stderr, err := runWithOutput(t, workflowcmd.NewWorkflowCmd, `{"msg":"Workflow deleted"}`,
+			"delete", "--org", "not-an-actual-org", "--workflow-group", "not-an-actual-workflow-group",
+			"--workflow-id", "not-an-actual-workflow", "-o", "name")
+		assert.NoError(t, err)
+		assert.Equal(t, "Workflow deleted\n", stdout)
+		assert.Contains(t, stderr, "Workflow deleted successfully.\n")
+	})
+
+	t.Run("Artifacts_Table", func(t *testing.T) {
+		stdout, _, err := runWithOutput(t, artifactscmd.NewArtifactsCmd, artifactsListResponse,
+			"list", "--org", "not-an-actual-org", "--workflow-group", "not-an-actual-wfg", "--workflow-id", "not-an-actual-workflow", "-o", "table")
+		assert.NoError(t, err)
+		assert.Equal(t, ""+
+			"NAME                     SIZE   LAST MODIFIED\n"+
+			"artifacts/tfstate.json   6548   2024-10-17 15:54:00+00:00\n", stdout)
+	})
+
+	t.Run("Invalid_Format", func(t *testing.T) {
+		_, _, err := runWithOutput(t, workflowcmd.NewWorkflowCmd, workflowListResponse, append(list, "-o", "xml")...)
+		assert.ErrorContains(t, err, `unknown output format "xml"`)
+	})
+
+	t.Run("Template_Required", func(t *testing.T) {
+		_, _, err := runWithOutput(t, workflowcmd.NewWorkflowCmd, workflowListResponse, append(list, "-o", "jsonpath")...)
+		assert.ErrorContains(t, err, "output format jsonpath requires a template")
+	})
+}
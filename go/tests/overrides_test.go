@@ -0,0 +1,196 @@
+package tests
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	workflowcmd "github.com/StackGuardian/sg-cli/cmd/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+const vcsWorkflow = `{"msg": {
+    "ResourceName": "not-an-actual-workflow",
+    "EnvironmentVariables": [
+        {"kind": "PLAIN_TEXT", "config": {"varName": "REGION", "textValue": "eu-central-1"}},
+        {"kind": "VAULT_SECRET", "config": {"varName": "TOKEN", "secretId": "/secrets/token"}}
+    ],
+    "VCSConfig": {
+        "iacVCSConfig": {
+            "useMarketplaceTemplate": false,
+            "customSource": {"sourceConfigDestKind": "GITHUB_COM", "config": {"repo": "https://github.com/example/infra", "ref": "main"}}
+        },
+        "iacInputData": {"schemaType": "FORM_JSONSCHEMA", "data": {"bucket_region": "eu-central-1", "replicas": 1}}
+    }
+}}`
+
+// runWorkflow runs a workflow command against the answers of sequence
+func runWorkflow(t *testing.T, sequence *sequenceTransport, args ...string) (string, string, error) {
+	t.Helper()
+	return runWorkflowWithInput(t, sequence, "", args...)
+}
+
+// runWorkflowWithInput runs a workflow command with input as the answers to its prompts
+func runWorkflowWithInput(t *testing.T, sequence *sequenceTransport, input string, args ...string) (string, string, error) {
+	t.Helper()
+	return runCmd(t, workflowcmd.NewWorkflowCmd, sequence, input, args, append(testFlags, "--workflow-id", "not-an-actual-workflow")...)
+}
+
+func TestApplyOverrides(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("Overrides_Merge_With_Workflow", func(t *testing.T) {
+		envFile := write("run.env", "# defaults\nexport REGION=us-east-1\nDEBUG=\"true\"\n")
+		varFile := write("run.tfvars", "replicas = 3\nzones = [\"a\", \"b\"]\n")
+		sequence := &sequenceTransport{bodies: []string{vcsWorkflow, createdRun}}
+		_, _, err := runWorkflow(t, sequence, "apply", "--env-file", envFile, "--env", "DEBUG=false",
+			"--var-file", varFile, "--tf-var", "bucket_region=us-east-1", "--vcs-ref", "feature/x", "--runner-group", "on-prem")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"GET /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow",
+			"POST /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/",
+		}, sequence.paths)
+
+		var sent map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(sequence.sent[1]), &sent))
+		assert.Equal(t, map[string]interface{}{"action": "apply"}, sent["TerraformAction"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"kind": "PLAIN_TEXT", "config": map[string]interface{}{"varName": "REGION", "textValue": "us-east-1"}},
+			map[string]interface{}{"kind": "VAULT_SECRET", "config": map[string]interface{}{"varName": "TOKEN", "secretId": "/secrets/token"}},
+			map[string]interface{}{"kind": "PLAIN_TEXT", "config": map[string]interface{}{"varName": "DEBUG", "textValue": "false"}},
+		}, sent["EnvironmentVariables"])
+		vcs := sent["VCSConfig"].(map[string]interface{})
+		assert.Equal(t, "feature/x", vcs["iacVCSConfig"].(map[string]interface{})["customSource"].(map[string]interface{})["config"].(map[string]interface{})["ref"])
+		assert.Equal(t, map[string]interface{}{
+			"schemaType": "FORM_JSONSCHEMA",
+			"data":       map[string]interface{}{"bucket_region": "us-east-1", "replicas": float64(3), "zones": []interface{}{"a", "b"}},
+		}, vcs["iacInputData"])
+		assert.Equal(t, map[string]interface{}{"type": "private", "names": []interface{}{"on-prem"}}, sent["RunnerConstraints"])
+	})
+
+	t.Run("Dry_Run_With_Run_Payload", func(t *testing.T) {
+		payload := write("run.json", `{"TerraformAction": {"action": "apply"}, "UserJobCPU": 1024}`)
+		sequence := &sequenceTransport{bodies: []string{createdRun}}
+		stdout, _, err := runWorkflow(t, sequence, "apply", "--run-payload", payload, "--runner-group", "on-prem", "--dry-run")
+		assert.NoError(t, err)
+		assert.Empty(t, sequence.paths)
+		var printed map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(stdout), &printed))
+		assert.Equal(t, map[string]interface{}{
+			"TerraformAction":   map[string]interface{}{"action": "apply"},
+			"UserJobCPU":        float64(1024),
+			"RunnerConstraints": map[string]interface{}{"type": "private", "names": []interface{}{"on-prem"}},
+		}, printed)
+
+		// Without an action, the payload runs the action of the command
+		payload = write("no-action.json", `{"UserJobCPU": 1024}`)
+		stdout, _, err = runWorkflow(t, sequence, "apply", "--run-payload", payload, "--dry-run")
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal([]byte(stdout), &printed))
+		assert.Equal(t, map[string]interface{}{"action": "apply"}, printed["TerraformAction"])
+	})
+
+	t.Run("Run_Payload_Other_Action", func(t *testing.T) {
+		payload := write("destroy.json", `{"TerraformAction": {"action": "destroy"}}`)
+		sequence := &sequenceTransport{bodies: []string{createdRun}}
+		_, _, err := runWorkflow(t, sequence, "apply", "--run-payload", payload)
+		assert.Equal(t, 2, clierrors.ExitCode(err), err)
+		assert.ErrorContains(t, err, "TerraformAction.action to destroy")
+		assert.Empty(t, sequence.paths)
+	})
+
+	t.Run("Run_Payload_Unknown_Field", func(t *testing.T) {
+		payload := write("unknown.json", `{"TerraformAction": {"action": "apply"}, "EnvVars": []}`)
+		sequence := &sequenceTransport{bodies: []string{createdRun}}
+		_, _, err := runWorkflow(t, sequence, "apply", "--run-payload", payload)
+		assert.Equal(t, 5, clierrors.ExitCode(err), err)
+		assert.ErrorContains(t, err, "unknown fields in the workflow run payload: EnvVars")
+		assert.Empty(t, sequence.paths)
+	})
+
+	t.Run("Run_Payload_Invalid_Environment", func(t *testing.T) {
+		payload := write("environment.json", `{"EnvironmentVariables": ["FOO"]}`)
+		sequence := &sequenceTransport{bodies: []string{createdRun}}
+		_, _, err := runWorkflow(t, sequence, "apply", "--run-payload", payload, "--env", "A=B")
+		assert.Equal(t, 5, clierrors.ExitCode(err), err)
+		assert.Empty(t, sequence.paths)
+	})
+
+	t.Run("Invalid_Env", func(t *testing.T) {
+		_, _, err := runWorkflow(t, &sequenceTransport{bodies: []string{createdRun}}, "apply", "--env", "REGION")
+		assert.Equal(t, 2, clierrors.ExitCode(err), err)
+	})
+
+	t.Run("Vcs_Ref_Requires_Custom_Source", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{`{"msg": {"VCSConfig": {"iacVCSConfig": {"useMarketplaceTemplate": true, "iacTemplateId": "/demo/template:1"}}}}`}}
+		_, _, err := runWorkflow(t, sequence, "apply", "--vcs-ref", "v1.2.0")
+		assert.Equal(t, 2, clierrors.ExitCode(err), err)
+		assert.Len(t, sequence.paths, 1)
+	})
+}
+
+func TestTargetedRuns(t *testing.T) {
+	const workflow = `{"msg": {"TerraformConfig": {"managedTerraformState": true, "terraformPlanOptions": "-lock-timeout=60s"}}}`
+
+	t.Run("Apply_Confirmed", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{workflow, createdRun}}
+		_, stderr, err := runWorkflowWithInput(t, sequence, "y\n", "apply", "--target", "aws_instance.web", "--target", "module.db", "--replace", "aws_instance.web")
+		assert.NoError(t, err)
+		assert.Contains(t, stderr, ">> [WARNING] This apply of workflow not-an-actual-workflow is a targeted run:\n     -target=aws_instance.web\n     -target=module.db\n     -replace=aws_instance.web\n")
+		assert.Contains(t, stderr, "Start the targeted apply? [y/N]: ")
+		assert.Len(t, sequence.paths, 2)
+
+		var sent map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(sequence.sent[1]), &sent))
+		assert.Equal(t, map[string]interface{}{
+			"managedTerraformState": true,
+			"terraformPlanOptions":  "-lock-timeout=60s -target=aws_instance.web -target=module.db -replace=aws_instance.web",
+		}, sent["TerraformConfig"])
+	})
+
+	t.Run("Apply_Declined", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{workflow, createdRun}}
+		_, _, err := runWorkflowWithInput(t, sequence, "n\n", "apply", "--target", "aws_instance.web")
+		assert.Equal(t, 1, clierrors.ExitCode(err), err)
+		assert.Len(t, sequence.paths, 1)
+	})
+
+	t.Run("Destroy_Yes", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{workflow, createdRun}}
+		_, _, err := runWorkflow(t, sequence, "destroy", "--target", "aws_instance.web", "--yes")
+		assert.NoError(t, err)
+		var sent map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(sequence.sent[1]), &sent))
+		assert.Equal(t, map[string]interface{}{"action": "destroy"}, sent["TerraformAction"])
+		assert.Equal(t, "-lock-timeout=60s -target=aws_instance.web", sent["TerraformConfig"].(map[string]interface{})["terraformPlanOptions"])
+	})
+
+	t.Run("Plan_Refresh_Only_Dry_Run", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{`{"msg": {}}`}}
+		stdout, _, err := runWorkflow(t, sequence, "plan", "--refresh-only", "--dry-run")
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, `"terraformPlanOptions": "-refresh-only"`)
+	})
+
+	t.Run("Invalid_Combinations", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"destroy", "--refresh-only"},
+			{"apply", "--refresh-only", "--replace", "aws_instance.web"},
+			{"plan", "--target", "aws_instance.web aws_instance.db"},
+		} {
+			sequence := &sequenceTransport{bodies: []string{workflow}}
+			_, _, err := runWorkflow(t, sequence, args...)
+			assert.Equal(t, 2, clierrors.ExitCode(err), args)
+			assert.Empty(t, sequence.paths, args)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	"github.com/StackGuardian/sg-cli/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+const planArtifacts = `{
+    "msg": "Artifacts",
+    "data": {
+        "artifacts": {
+            "wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/not-an-actual-workflow-run/artifacts/tfstate.json": {"url": "https://bucket.example.com/tfstate.json"},
+            "wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/not-an-actual-workflow-run/artifacts/checkov-plan.json": {"url": "https://bucket.example.com/checkov-plan.json"},
+            "wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/not-an-actual-workflow-run/artifacts/plan.json": {"url": "https://bucket.example.com/plan.json"},
+            "wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/run-2/artifacts/plan.json": {"url": "https://bucket.example.com/run-2/plan.json"}
+        }
+    }
+}`
+
+const planWithChanges = `{
+    "format_version": "1.2",
+    "resource_changes": [
+        {"address": "aws_db_instance.main", "change": {"actions": ["delete", "create"]}},
+        {"address": "aws_iam_role.old", "change": {"actions": ["delete"]}},
+        {"address": "aws_instance.web", "change": {"actions": ["update"]}},
+        {"address": "aws_s3_bucket.logs", "change": {"actions": ["create"]}},
+        {"address": "aws_vpc.main", "change": {"actions": ["no-op"]}},
+        {"address": "data.aws_ami.ubuntu", "change": {"actions": ["read"]}}
+    ]
+}`
+
+const planWithoutChanges = `{"format_version": "1.2", "resource_changes": [{"address": "aws_vpc.main", "change": {"actions": ["no-op"]}}]}`
+
+func TestPlanWorkflow(t *testing.T) {
+	t.Cleanup(func() { transport.SetClient(nil) })
+
+	cases := []struct {
+		name             string
+		args             []string
+		bodies           []string
+		expectedExitCode int
+		expectedOutput   string
+	}{
+		{
+			name:   "Changes",
+			args:   []string{"plan", "--poll-interval", "1ms"},
+			bodies: []string{createdRun, runStatus("RUNNING"), runStatus("COMPLETED"), planArtifacts, planWithChanges},
+			expectedOutput: `Terraform will perform the following actions:
+
+-/+ aws_db_instance.main must be replaced
+  - aws_iam_role.old will be destroyed
+  ~ aws_instance.web will be updated in-place
+  + aws_s3_bucket.logs will be created
+
+Plan: 1 to add, 1 to change, 1 to replace, 1 to destroy.
+`,
+		},
+		{
+			name:             "Detailed_Exit_Code_Changes",
+			args:             []string{"plan", "--poll-interval", "1ms", "--detailed-exitcode"},
+			bodies:           []string{createdRun, runStatus("COMPLETED"), planArtifacts, planWithChanges},
+			expectedExitCode: 2,
+		},
+		{
+			name:           "Detailed_Exit_Code_No_Changes",
+			args:           []string{"plan", "--poll-interval", "1ms", "--detailed-exitcode"},
+			bodies:         []string{createdRun, runStatus("COMPLETED"), planArtifacts, planWithoutChanges},
+			expectedOutput: "No changes. Your infrastructure matches the configuration.\n",
+		},
+		{
+			name:             "Run_Errored",
+			args:             []string{"plan", "--poll-interval", "1ms", "--detailed-exitcode"},
+			bodies:           []string{createdRun, runStatus("ERRORED")},
+			expectedExitCode: 11,
+		},
+		{
+			name:             "Not_A_Plan",
+			args:             []string{"plan", "--poll-interval", "1ms", "--detailed-exitcode"},
+			bodies:           []string{createdRun, runStatus("COMPLETED"), planArtifacts, `{"results": {"failed_checks": []}}`},
+			expectedExitCode: 5,
+		},
+		{
+			name:             "No_Plan_Artifact",
+			args:             []string{"plan", "--poll-interval", "1ms"},
+			bodies:           []string{createdRun, runStatus("COMPLETED"), `{"msg": "Artifacts", "data": {"artifacts": {}}}`},
+			expectedExitCode: 4,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sequence := &sequenceTransport{bodies: tc.bodies}
+			transport.SetClient(&http.Client{Transport: sequence})
+			stdout, _, err := runWorkflow(t, sequence, tc.args...)
+			assert.Equal(t, tc.expectedExitCode, clierrors.ExitCode(err), err)
+			assert.Contains(t, stdout, tc.expectedOutput)
+			assert.JSONEq(t, `{"TerraformAction": {"action": "plan"}}`, sequence.sent[0])
+		})
+	}
+
+	t.Run("Run_Payload_Action", func(t *testing.T) {
+		dir := t.TempDir()
+		for action, expectedExitCode := range map[string]int{"apply": 2, "destroy": 2, "plan-destroy": 0} {
+			payload := filepath.Join(dir, action+".json")
+			assert.NoError(t, os.WriteFile(payload, []byte(`{"TerraformAction": {"action": "`+action+`"}}`), 0o600))
+			sequence := &sequenceTransport{bodies: []string{createdRun, runStatus("COMPLETED"), planArtifacts, planWithoutChanges}}
+			transport.SetClient(&http.Client{Transport: sequence})
+			_, _, err := runWorkflow(t, sequence, "plan", "--poll-interval", "1ms", "--run-payload", payload)
+			assert.Equal(t, expectedExitCode, clierrors.ExitCode(err), action, err)
+			if expectedExitCode != 0 {
+				// A plan never starts a run that changes resources
+				assert.Empty(t, sequence.paths, action)
+			} else {
+				assert.JSONEq(t, `{"TerraformAction": {"action": "plan-destroy"}}`, sequence.sent[0])
+			}
+		}
+	})
+
+	t.Run("Output_JSON", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{createdRun, runStatus("COMPLETED"), planArtifacts, planWithChanges}}
+		transport.SetClient(&http.Client{Transport: sequence})
+		stdout, _, err := runWorkflow(t, sequence, "plan", "--poll-interval", "1ms", "-o", "json")
+		assert.NoError(t, err)
+		assert.Equal(t, "GET /plan.json", sequence.paths[len(sequence.paths)-1])
+		var printed map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(stdout), &printed))
+		assert.Equal(t, map[string]interface{}{"add": float64(1), "change": float64(1), "replace": float64(1), "destroy": float64(1)}, printed["summary"])
+		assert.Equal(t, map[string]interface{}{"address": "aws_s3_bucket.logs", "action": "add"}, printed["msg"].([]interface{})[3])
+	})
+}
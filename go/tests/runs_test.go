@@ -0,0 +1,380 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	workflowcmd "github.com/StackGuardian/sg-cli/cmd/workflow"
+	"github.com/StackGuardian/sg-cli/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+const runsFirstPage = `{
+    "lastevaluatedkey": "page-2",
+    "msg": [
+        {
+            "ResourceName": "run-3",
+            "LatestStatus": "ERRORED",
+            "Authors": ["jane@example.com"],
+            "CreatedAt": 1729823822294,
+            "ModifiedAt": 1729823912294,
+            "RuntimeParameters": {"terraformAction": {"action": "apply"}},
+            "TriggerDetails": {"commitId": "0123456789abcdef0123456789abcdef01234567"}
+        },
+        {
+            "ResourceName": "run-2",
+            "LatestStatus": "COMPLETED",
+            "Authors": ["john@example.com"],
+            "CreatedAt": 1729737422294,
+            "ModifiedAt": 1729737482294,
+            "RuntimeParameters": {"terraformAction": {"action": "plan"}}
+        }
+    ]
+}`
+
+const runsLastPage = `{
+    "lastevaluatedkey": "",
+    "msg": [
+        {
+            "ResourceName": "run-1",
+            "LatestStatus": "ERRORED",
+            "Authors": ["jane@example.com"],
+            "CreatedAt": 1729651022294,
+            "ModifiedAt": 1729651142294,
+            "RuntimeParameters": {"terraformAction": {"action": "destroy"}}
+        }
+    ]
+}`
+
+const runRead = `{
+    "msg": {
+        "ResourceName": "run-3",
+        "LatestStatus": "ERRORED",
+        "Authors": ["jane@example.com"],
+        "CreatedAt": 1729823822294,
+        "ModifiedAt": 1729823912294,
+        "RuntimeParameters": {"terraformAction": {"action": "apply"}},
+        "EnvironmentVariables": [{"kind": "PLAIN_TEXT", "config": {"varName": "TF_LOG", "textValue": "INFO"}}],
+        "Statuses": {
+            "apply": [{"name": "ERRORED", "createdAt": 1729823912294}],
+            "pre_0_step": [{"name": "QUEUED", "createdAt": 1729823822294}, {"name": "COMPLETED", "createdAt": 1729823852294}]
+        }
+    }
+}`
+
+const runArtifacts = `{
+    "msg": "Outputs retrieved",
+    "data": {
+        "artifacts": {
+            "wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/run-3/artifacts/plan.json": {"url": "https://bucket.example.com/run-3/plan.json", "size": 12},
+            "wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/run-2/artifacts/plan.json": {"url": "https://bucket.example.com/run-2/plan.json", "size": 10}
+        }
+    }
+}`
+
+func runRuns(t *testing.T, sequence *sequenceTransport, args ...string) (string, string, error) {
+	t.Helper()
+	return runRunsWithInput(t, sequence, "", args...)
+}
+
+// runRunsWithInput runs a workflow runs command with input as the answers to its prompts
+func runRunsWithInput(t *testing.T, sequence *sequenceTransport, input string, args ...string) (string, string, error) {
+	t.Helper()
+	return runCmd(t, workflowcmd.NewWorkflowCmd, sequence, input, append([]string{"runs"}, args...),
+		append(testFlags, "--workflow-id", "not-an-actual-workflow")...)
+}
+
+func TestListWorkflowRuns(t *testing.T) {
+	t.Run("Table_All_Pages", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{runsFirstPage, runsLastPage}}
+		stdout, _, err := runRuns(t, sequence, "list")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"", "lastevaluatedkey=page-2"}, sequence.queries)
+		assert.Contains(t, sequence.paths, "GET /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/listall/")
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		assert.Len(t, lines, 4)
+		assert.Equal(t, []string{"RUN", "ID", "ACTION", "STATUS", "INITIATOR", "STARTED", "DURATION", "COMMIT"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{"run-3", "apply", "ERRORED", "jane@example.com", "2024-10-25T02:37:02Z", "1m30s", "0123456789ab"}, strings.Fields(lines[1]))
+		assert.Equal(t, []string{"run-1", "destroy", "ERRORED", "jane@example.com", "2024-10-23T02:37:02Z", "2m0s"}, strings.Fields(lines[3]))
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{runsFirstPage, runsLastPage}}
+		stdout, _, err := runRuns(t, sequence, "list", "--status", "errored", "--since", "2024-10-23T12:00:00Z", "-o", "name")
+		assert.NoError(t, err)
+		assert.Equal(t, "run-3\n", stdout)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{runsFirstPage, runsLastPage}}
+		stdout, stderr, err := runRuns(t, sequence, "list", "--limit", "2", "-o", "name")
+		assert.NoError(t, err)
+		assert.Equal(t, "run-3\nrun-2\n", stdout)
+		assert.Len(t, sequence.paths, 1)
+		assert.Contains(t, stderr, "Showing the first 2 matching runs")
+	})
+
+	t.Run("Invalid_Since", func(t *testing.T) {
+		_, _, err := runRuns(t, &sequenceTransport{bodies: []string{runsLastPage}}, "list", "--since", "yesterday")
+		assert.Equal(t, 2, clierrors.ExitCode(err))
+	})
+}
+
+func TestReadWorkflowRun(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{runRead, runArtifacts}}
+		stdout, _, err := runRuns(t, sequence, "read", "run-3", "-o", "json")
+		assert.NoError(t, err)
+		assert.Equal(t, "GET /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/run-3/", sequence.paths[0])
+
+		var doc struct {
+			Msg   map[string]interface{}
+			Steps []struct {
+				Step   string
+				Status string
+			}
+			Inputs    map[string]interface{}
+			Artifacts map[string]interface{}
+		}
+		assert.NoError(t, json.Unmarshal([]byte(stdout), &doc))
+		assert.Equal(t, "run-3", doc.Msg["ResourceName"])
+		assert.Len(t, doc.Steps, 3)
+		assert.Equal(t, "pre_0_step", doc.Steps[0].Step)
+		assert.Equal(t, "ERRORED", doc.Steps[2].Status)
+		assert.Contains(t, doc.Inputs, "RuntimeParameters")
+		assert.Contains(t, doc.Inputs, "EnvironmentVariables")
+		assert.Len(t, doc.Artifacts, 1)
+	})
+
+	t.Run("Summary", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{runRead, runArtifacts}}
+		stdout, _, err := runRuns(t, sequence, "read", "run-3")
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, "Action:      apply\n")
+		assert.Contains(t, stdout, "Duration:    1m30s\n")
+		assert.Contains(t, stdout, "  apply        ERRORED     2024-10-25T02:38:32Z\n")
+		assert.Contains(t, stdout, "\"varName\": \"TF_LOG\"")
+		assert.Contains(t, stdout, "wfruns/run-3/artifacts/plan.json\n")
+		assert.NotContains(t, stdout, "wfruns/run-2/")
+	})
+
+	t.Run("No_Artifacts", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{runRead, ""}}
+		_, _, err := runRuns(t, sequence, "read", "run-3", "-o", "json")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Requires_Run", func(t *testing.T) {
+		_, _, err := runRuns(t, &sequenceTransport{bodies: []string{runRead}}, "read")
+		assert.Error(t, err)
+	})
+}
+
+func runLogs(urls ...string) string {
+	entries := make([]string, 0, len(urls))
+	for _, u := range urls {
+		entries = append(entries, `{"logURL": "https://logs.example.com/wfruns/run-3/`+u+`?X-Amz-Signature=secret"}`)
+	}
+	return `{"msg": [` + strings.Join(entries, ", ") + `]}`
+}
+
+func TestWorkflowRunLogs(t *testing.T) {
+	t.Cleanup(func() { transport.SetClient(nil) })
+
+	cases := []struct {
+		name             string
+		args             []string
+		bodies           []string
+		expectedExitCode int
+		expectedOutput   string
+	}{
+		{
+			name:           "All_Steps",
+			args:           []string{"logs", "run-3"},
+			bodies:         []string{runStatus("COMPLETED"), runLogs("pre_0_step.log", "apply.log"), "init\n", "applied"},
+			expectedOutput: ">> Step pre_0_step\ninit\n>> Step apply\napplied\n",
+		},
+		{
+			name:           "Step",
+			args:           []string{"logs", "run-3", "--step", "apply"},
+			bodies:         []string{runStatus("COMPLETED"), runLogs("pre_0_step.log", "apply.log"), "applied\n"},
+			expectedOutput: ">> Step apply\napplied\n",
+		},
+		{
+			name:             "Unknown_Step",
+			args:             []string{"logs", "run-3", "--step", "plan"},
+			bodies:           []string{runStatus("COMPLETED"), runLogs("pre_0_step.log")},
+			expectedExitCode: 4,
+		},
+		{
+			name: "Follow",
+			args: []string{"logs", "run-3", "--follow", "--poll-interval", "1ms"},
+			bodies: []string{runStatus("RUNNING"), runLogs("pre_0_step.log"), "line 1\n",
+				runStatus("ERRORED"), runLogs("pre_0_step.log", "apply.log"), "line 1\nline 2\n", "failed\n"},
+			expectedExitCode: 11,
+			expectedOutput:   ">> Step pre_0_step\nline 1\nline 2\n>> Step apply\nfailed\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sequence := &sequenceTransport{bodies: tc.bodies}
+			transport.SetClient(&http.Client{Transport: sequence})
+			stdout, _, err := runRuns(t, sequence, tc.args...)
+			assert.Equal(t, tc.expectedExitCode, clierrors.ExitCode(err), err)
+			if tc.expectedOutput != "" {
+				assert.Equal(t, tc.expectedOutput, stdout)
+			}
+			assert.Equal(t, "GET /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/run-3/logs/", sequence.paths[1])
+		})
+	}
+}
+
+const pendingWorkflows = `{
+    "msg": [
+        {"ResourceName": "not-an-actual-workflow", "LatestWfrunStatus": "APPROVAL_REQUIRED"},
+        {"ResourceName": "not-an-actual-workflow-2", "LatestWfrunStatus": "COMPLETED"}
+    ]
+}`
+
+const pendingRuns = `{
+    "msg": [
+        {"ResourceName": "run-3", "LatestStatus": "APPROVAL_REQUIRED"},
+        {"ResourceName": "run-2", "LatestStatus": "COMPLETED"}
+    ]
+}`
+
+const approvedRun = `{"msg": "Workflow Run resumed"}`
+
+func TestApproveWorkflowRuns(t *testing.T) {
+	t.Cleanup(func() { transport.SetClient(nil) })
+	resume := "POST /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/run-3/resume/"
+	pendingBodies := []string{pendingWorkflows, pendingRuns, runLogs("plan.log"),
+		"\x1b[1mPlan:\x1b[0m 1 to add, 0 to change, 0 to destroy.\n", approvedRun}
+
+	cases := []struct {
+		name             string
+		args             []string
+		input            string
+		bodies           []string
+		expectedExitCode int
+		expectedResumes  int
+		expectedOutput   []string
+	}{
+		{
+			name:            "Approve_Run",
+			args:            []string{"approve", "run-3", "--comment", "LGTM"},
+			bodies:          []string{approvedRun},
+			expectedResumes: 1,
+			expectedOutput:  []string{"Workflow run run-3 approved."},
+		},
+		{
+			name:            "Reject_Run",
+			args:            []string{"reject", "run-3"},
+			bodies:          []string{approvedRun},
+			expectedResumes: 1,
+			expectedOutput:  []string{"Workflow run run-3 rejected."},
+		},
+		{
+			name:             "Requires_Run_Or_Pending",
+			args:             []string{"approve"},
+			bodies:           []string{approvedRun},
+			expectedExitCode: 2,
+		},
+		{
+			name:            "Pending_Yes",
+			args:            []string{"approve", "--pending", "--yes"},
+			bodies:          pendingBodies,
+			expectedResumes: 1,
+			expectedOutput:  []string{"Plan: 1 to add, 0 to change, 0 to destroy.", ">> Workflow run run-3 of not-an-actual-workflow approved."},
+		},
+		{
+			name:            "Pending_Selected",
+			args:            []string{"approve", "--pending"},
+			input:           "1\n",
+			bodies:          pendingBodies,
+			expectedResumes: 1,
+			expectedOutput:  []string{">> Workflow run run-3 of not-an-actual-workflow approved."},
+		},
+		{
+			name:            "Pending_None",
+			args:            []string{"approve", "--pending"},
+			input:           "\n",
+			bodies:          pendingBodies,
+			expectedResumes: 0,
+			expectedOutput:  []string{"Plan: 1 to add, 0 to change, 0 to destroy."},
+		},
+		{
+			name:             "Pending_Invalid_Selection",
+			args:             []string{"approve", "--pending"},
+			input:            "7\n",
+			bodies:           pendingBodies,
+			expectedExitCode: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sequence := &sequenceTransport{bodies: tc.bodies}
+			transport.SetClient(&http.Client{Transport: sequence})
+			stdout, _, err := runRunsWithInput(t, sequence, tc.input, tc.args...)
+			assert.Equal(t, tc.expectedExitCode, clierrors.ExitCode(err), err)
+			resumes := 0
+			for _, path := range sequence.paths {
+				if path == resume {
+					resumes++
+				}
+			}
+			assert.Equal(t, tc.expectedResumes, resumes)
+			for _, expected := range tc.expectedOutput {
+				assert.Contains(t, stdout, expected)
+			}
+		})
+	}
+}
+
+func TestCancelAndRerunWorkflowRuns(t *testing.T) {
+	t.Run("Cancel_Wait", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{`{"msg": "Workflow Run cancelled"}`, runStatus("RUNNING"), runStatus("CANCELLED")}}
+		_, _, err := runRuns(t, sequence, "cancel", "not-an-actual-workflow-run", "--wait", "--poll-interval", "1ms")
+		assert.NoError(t, err)
+		assert.Equal(t, "PATCH /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/not-an-actual-workflow-run/cancel/", sequence.paths[0])
+		assert.Len(t, sequence.paths, 3)
+	})
+
+	t.Run("Rerun", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{runRead, createdRun}}
+		stdout, _, err := runRuns(t, sequence, "rerun", "run-3", "--action", "plan", "--patch-payload", `{"UserJobCPU": 2}`)
+		assert.NoError(t, err)
+		assert.Equal(t, "POST /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/", sequence.paths[1])
+		assert.JSONEq(t, `{
+			"TerraformAction": {"action": "plan"},
+			"EnvironmentVariables": [{"kind": "PLAIN_TEXT", "config": {"varName": "TF_LOG", "textValue": "INFO"}}],
+			"UserJobCPU": 2
+		}`, sequence.sent[1])
+		assert.Contains(t, stdout, "Workflow run run-3 rerun as not-an-actual-workflow-run.")
+	})
+
+	t.Run("Rerun_Patch_Not_An_Object", func(t *testing.T) {
+		for _, patch := range []string{"[]", "null"} {
+			sequence := &sequenceTransport{bodies: []string{runRead, createdRun}}
+			_, _, err := runRuns(t, sequence, "rerun", "run-3", "--patch-payload", patch)
+			assert.Equal(t, 5, clierrors.ExitCode(err), patch)
+			assert.Empty(t, sequence.paths)
+		}
+	})
+
+	t.Run("Rerun_Wait_Failed", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{runRead, createdRun, runStatus("FAILED")}}
+		_, _, err := runRuns(t, sequence, "rerun", "run-3", "--wait", "--poll-interval", "1ms")
+		assert.Equal(t, 11, clierrors.ExitCode(err))
+	})
+
+	t.Run("Rerun_Invalid_Action", func(t *testing.T) {
+		_, _, err := runRuns(t, &sequenceTransport{bodies: []string{runRead}}, "rerun", "run-3", "--action", "deploy")
+		assert.Equal(t, 2, clierrors.ExitCode(err))
+	})
+}
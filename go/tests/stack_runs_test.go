@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	stackcmd "github.com/StackGuardian/sg-cli/cmd/stack"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	option "github.com/StackGuardian/sg-sdk-go/option"
+	"github.com/stretchr/testify/assert"
+)
+
+const createdStackRun = `{"msg": "Stack run created", "data": {"workflowruns": [], "StackRunId": "/stackruns/not-an-actual-stack-run"}}`
+
+// stackRunStatus returns a stack run with a network workflow and a database workflow depending on it
+func stackRunStatus(status, network, database string) string {
+	return `{"msg": {"ResourceName": "not-an-actual-stack-run", "LatestStatus": "` + status + `", "WfRuns": [
+		{"ResourceName": "run-network", "LatestStatus": "` + network + `", "CreatedAt": 1730113178197, "ModifiedAt": 1730113258197,
+		 "SubResourceId": "/wfgrps/not-an-actual-workflow-group/stacks/not-an-actual-stack/wfs/network/wfruns/run-network"},
+		{"ResourceName": "run-database", "LatestStatus": "` + database + `", "CreatedAt": 1730113258197, "ModifiedAt": 1730113293197,
+		 "SubResourceId": "/wfgrps/not-an-actual-workflow-group/stacks/not-an-actual-stack/wfs/database/wfruns/run-database",
+		 "DependsOn": ["network"]}
+	]}}`
+}
+
+func TestWatchStackRun(t *testing.T) {
+	flags := []string{"--org", "not-an-actual-org", "--workflow-group", "not-an-actual-workflow-group",
+		"--stack-id", "not-an-actual-stack", "--poll-interval", "1ms"}
+
+	cases := []struct {
+		name             string
+		args             []string
+		bodies           []string
+		timeout          time.Duration
+		expectedExitCode int
+		expectedOutput   []string
+		expectedRequests int
+	}{
+		{
+			name: "Apply_Wait_Completed",
+			args: []string{"apply", "--wait"},
+			bodies: []string{createdStackRun, stackRunStatus("RUNNING", "RUNNING", "QUEUED"), stackRunStatus("RUNNING", "RUNNING", "QUEUED"),
+				stackRunStatus("COMPLETED", "COMPLETED", "COMPLETED")},
+			expectedExitCode: 0,
+			expectedOutput: []string{
+				"] Stack run not-an-actual-stack-run: RUNNING\nnetwork       run-network    RUNNING   1m20s\n└─ database   run-database   QUEUED    35s\n",
+				"] Stack run not-an-actual-stack-run: COMPLETED\n",
+				"https://app.stackguardian.io/orchestrator/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/stacks/not-an-actual-stack/stackruns/not-an-actual-stack-run\n",
+			},
+			expectedRequests: 4,
+		},
+		{
+			name:             "Destroy_Wait_Errored",
+			args:             []string{"destroy", "--wait"},
+			bodies:           []string{createdStackRun, stackRunStatus("RUNNING", "COMPLETED", "RUNNING"), stackRunStatus("ERRORED", "COMPLETED", "ERRORED")},
+			expectedExitCode: 11,
+			expectedOutput: []string{
+				">> Workflow database ERRORED: https://app.stackguardian.io/orchestrator/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/stacks/not-an-actual-stack/wfs/database/wfruns/run-database\n",
+			},
+			expectedRequests: 3,
+		},
+		{
+			name:             "Watch_Cancelled",
+			args:             []string{"runs", "watch", "not-an-actual-stack-run"},
+			bodies:           []string{stackRunStatus("CANCELLED", "COMPLETED", "CANCELLED")},
+			expectedExitCode: 12,
+			expectedOutput:   []string{"] Stack run not-an-actual-stack-run: CANCELLED\n"},
+			expectedRequests: 1,
+		},
+		{
+			name:             "Watch_Timeout",
+			args:             []string{"runs", "watch", "not-an-actual-stack-run"},
+			bodies:           []string{stackRunStatus("RUNNING", "RUNNING", "QUEUED")},
+			timeout:          50 * time.Millisecond,
+			expectedExitCode: 124,
+			expectedOutput:   []string{">> Stack run not-an-actual-stack-run is still RUNNING: "},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			transport := &sequenceTransport{bodies: tc.bodies}
+			cmd := stackcmd.NewStackCmd(client.NewClient(option.WithHTTPClient(&http.Client{Transport: transport})))
+			cmd.SetArgs(append(tc.args, flags...))
+			b := &bytes.Buffer{}
+			cmd.SetOut(b)
+			cmd.SetErr(io.Discard)
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			// The global --timeout cancels the context of the command
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+			err := cmd.ExecuteContext(ctx)
+
+			assert.Equal(t, tc.expectedExitCode, clierrors.ExitCode(err), err)
+			for _, expected := range tc.expectedOutput {
+				assert.Contains(t, b.String(), expected)
+			}
+			// Polls without changes are not printed again
+			assert.LessOrEqual(t, bytes.Count(b.Bytes(), []byte("] Stack run not-an-actual-stack-run: RUNNING\n")), 1)
+			if tc.expectedRequests > 0 {
+				assert.Len(t, transport.paths, tc.expectedRequests)
+			}
+			assert.Contains(t, transport.paths, "GET /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/stacks/not-an-actual-stack/stackruns/not-an-actual-stack-run")
+		})
+	}
+}
@@ -5,7 +5,13 @@ import (
 	"io"
 	"net/http"
 	"os/exec"
+	"strings"
+	"testing"
 
+	"github.com/StackGuardian/sg-cli/output"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	option "github.com/StackGuardian/sg-sdk-go/option"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -46,8 +52,8 @@ const (
 	errNoSuchFile            = "no such file"
 	errMissingPayload        = "Error: accepts 1 arg(s), received 0"
 	errInvalidBulkJson       = "json: cannot unmarshal object into Go value of type []create.BulkWorkflow"
-	errorMissingResourceName = "Workflow ResourceName is required in object payload, skipping" // No file present
-	errStackNotEmpty         = "this stack cannot be deleted since it contains workflows"      // Stack contains workflows
+	errorMissingResourceName = "Workflow ResourceName is required in object payload"      // No file present
+	errStackNotEmpty         = "this stack cannot be deleted since it contains workflows" // Stack contains workflows
 
 )
 
@@ -66,6 +72,33 @@ func (m *mockSGSdkClient) RoundTrip(request *http.Request) (*http.Response, erro
 	}, nil
 }
 
+// testFlags select the org and workflow group of the tests
+var testFlags = []string{"--org", "not-an-actual-org", "--workflow-group", "not-an-actual-workflow-group"}
+
+// runCmd runs a command tree of sg-cli against the answers of rt, with the output flags of the root command.
+// flags are added to args before the "--" that ends the flags, input answers the prompts of the command.
+func runCmd(t *testing.T, newCmd func(*client.Client) *cobra.Command, rt http.RoundTripper, input string, args []string, flags ...string) (string, string, error) {
+	t.Helper()
+	cmd := newCmd(client.NewClient(option.WithHTTPClient(&http.Client{Transport: rt})))
+	output.AddFlags(cmd)
+	cmd.SetIn(strings.NewReader(input))
+	end := len(args)
+	for i, arg := range args {
+		if arg == "--" {
+			end = i
+			break
+		}
+	}
+	cmd.SetArgs(append(append(append([]string{}, args[:end]...), flags...), args[end:]...))
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	err := cmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
 // Helper function to run CLI commands
 func runCommand(binaryPath string, args []string) (string, error) {
 	cmd := exec.Command(binaryPath, args...)
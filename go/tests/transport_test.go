@@ -0,0 +1,265 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StackGuardian/sg-cli/config"
+	"github.com/StackGuardian/sg-cli/transport"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	option "github.com/StackGuardian/sg-sdk-go/option"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeClientCertificate generates a self-signed client certificate and returns the paths of the PEM files
+func writeClientCertificate(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "sg-cli test client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certificate, certFile, keyFile
+}
+
+func TestTransport(t *testing.T) {
+	dir := t.TempDir()
+	clientCertificate, certFile, keyFile := writeClientCertificate(t, dir)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(500 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"msg": {"ResourceName": "not-an-actual-org"}}`))
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCertificate)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(dir, "ca.pem")
+	assert.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	settings := func(profile config.Profile) *config.Settings {
+		return &config.Settings{ProfileName: config.DefaultProfile, Profile: profile, Sources: map[string]string{}}
+	}
+
+	t.Run("Untrusted_CA", func(t *testing.T) {
+		httpClient, err := transport.New(settings(config.Profile{}))
+		assert.NoError(t, err)
+		_, err = httpClient.Get(server.URL)
+		assert.ErrorContains(t, err, "certificate")
+	})
+
+	t.Run("Missing_Client_Certificate", func(t *testing.T) {
+		httpClient, err := transport.New(settings(config.Profile{CAFile: caFile}))
+		assert.NoError(t, err)
+		_, err = httpClient.Get(server.URL)
+		assert.Error(t, err)
+	})
+
+	t.Run("Mutual_TLS_With_SDK", func(t *testing.T) {
+		httpClient, err := transport.New(settings(config.Profile{CAFile: caFile, ClientCert: certFile, ClientKey: keyFile}))
+		assert.NoError(t, err)
+		c := client.NewClient(option.WithHTTPClient(httpClient), option.WithBaseURL(server.URL))
+		_, err = c.Organizations.ReadOrganization(context.Background(), "not-an-actual-org")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Request_Timeout", func(t *testing.T) {
+		httpClient, err := transport.New(settings(config.Profile{CAFile: caFile, ClientCert: certFile, ClientKey: keyFile, RequestTimeout: "100ms"}))
+		assert.NoError(t, err)
+		_, err = httpClient.Get(server.URL + "/slow")
+		assert.ErrorContains(t, err, "Timeout")
+	})
+
+	t.Run("Proxy", func(t *testing.T) {
+		var proxied string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied = r.URL.String()
+		}))
+		defer proxy.Close()
+		httpClient, err := transport.New(settings(config.Profile{Proxy: proxy.URL}))
+		assert.NoError(t, err)
+		resp, err := httpClient.Get("http://api.stackguardian.invalid/api/v1/orgs/")
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, "http://api.stackguardian.invalid/api/v1/orgs/", proxied)
+	})
+
+	t.Run("Invalid_Settings", func(t *testing.T) {
+		_, err := transport.New(settings(config.Profile{ClientCert: certFile}))
+		assert.ErrorContains(t, err, "must be set together")
+		_, err = transport.New(settings(config.Profile{RequestTimeout: "soon"}))
+		assert.ErrorContains(t, err, "invalid request-timeout")
+		_, err = transport.New(settings(config.Profile{CAFile: keyFile}))
+		assert.ErrorContains(t, err, "does not contain any PEM encoded certificate")
+	})
+}
+
+func TestTransportRetries(t *testing.T) {
+	var requests []string
+	failures := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+string(body))
+		if failures > 0 {
+			failures--
+			// Retry-After: 0 keeps the test fast
+			w.Header().Set("Retry-After", "0")
+			if r.URL.Path == "/rate-limited" {
+				w.WriteHeader(http.StatusTooManyRequests)
+			} else {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			return
+		}
+		w.Write([]byte(`{"msg": []}`))
+	}))
+	defer server.Close()
+
+	newClient := func(maxAttempts string) *http.Client {
+		httpClient, err := transport.New(&config.Settings{Profile: config.Profile{MaxAttempts: maxAttempts}, Sources: map[string]string{}})
+		assert.NoError(t, err)
+		return httpClient
+	}
+
+	cases := []struct {
+		name             string
+		maxAttempts      string
+		method           string
+		path             string
+		failures         int
+		expectedStatus   int
+		expectedRequests int
+	}{
+		{"Get_Retried_Until_Success", "", http.MethodGet, "/", 2, http.StatusOK, 3},
+		{"Max_Attempts", "2", http.MethodGet, "/", 3, http.StatusServiceUnavailable, 2},
+		{"Post_Not_Retried_On_5xx", "", http.MethodPost, "/", 1, http.StatusServiceUnavailable, 1},
+		{"Post_Retried_On_429", "", http.MethodPost, "/rate-limited", 1, http.StatusOK, 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests, failures = nil, tc.failures
+			retriesBefore := transport.Retries()
+			req, err := http.NewRequest(tc.method, server.URL+tc.path, strings.NewReader("payload"))
+			assert.NoError(t, err)
+			resp, err := newClient(tc.maxAttempts).Do(req)
+			assert.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+			assert.Len(t, requests, tc.expectedRequests)
+			assert.Equal(t, int64(tc.expectedRequests-1), transport.Retries()-retriesBefore)
+			// The body is sent again on every attempt
+			for _, request := range requests {
+				assert.Equal(t, tc.method+" payload", request)
+			}
+		})
+	}
+
+	t.Run("Invalid_Max_Attempts", func(t *testing.T) {
+		_, err := transport.New(&config.Settings{Profile: config.Profile{MaxAttempts: "0"}, Sources: map[string]string{}})
+		assert.ErrorContains(t, err, "invalid max-attempts")
+	})
+}
+
+func TestTransportDebug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "not-an-actual-request-id")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"msg": {"Settings": {"config": [{"textValue": "response-secret"}]},
+			"url": "https://bucket.example.com/tfstate.json?X-Amz-Signature=not-an-actual-response-signature"}}`))
+	}))
+	defer server.Close()
+	bucket := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not-an-actual-state"))
+	}))
+	defer bucket.Close()
+
+	log := &strings.Builder{}
+	transport.DebugLog = log
+	defer func() { transport.DebugLog = nil }()
+	transport.RecordHAR()
+
+	httpClient, err := transport.New(&config.Settings{Profile: config.Profile{APIURL: server.URL}, Sources: map[string]string{}})
+	assert.NoError(t, err)
+	c := client.NewClient(option.WithHTTPClient(httpClient), option.WithBaseURL(server.URL), option.WithApiKey("apikey sgu_not_an_actual_token"))
+	_, err = c.Organizations.ReadOrganization(context.Background(), "not-an-actual-org")
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/upload",
+		strings.NewReader(`{"awsSecretAccessKey": "request-secret", "awsAccessKeyId": "not-secret"}`))
+	assert.NoError(t, err)
+	resp, err := httpClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	// The bodies of pre-signed URLs and of other hosts are not captured
+	req, err = http.NewRequest(http.MethodPut, server.URL+"/tfstate.json?X-Amz-Signature=not-an-actual-signature", strings.NewReader("not-an-actual-state"))
+	assert.NoError(t, err)
+	resp, err = httpClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	resp, err = httpClient.Get(bucket.URL + "/tfstate.json")
+	assert.NoError(t, err)
+	state, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, "not-an-actual-state", string(state))
+
+	harFile := filepath.Join(t.TempDir(), "sg-cli.har")
+	assert.NoError(t, transport.WriteHAR(harFile, "test"))
+	har, err := os.ReadFile(harFile)
+	assert.NoError(t, err)
+
+	assert.Contains(t, log.String(), "--> GET "+server.URL+"/api/v1/orgs/not-an-actual-org/")
+	assert.Contains(t, log.String(), "<-- 200 OK GET")
+	assert.Contains(t, log.String(), "request-id: not-an-actual-request-id")
+	assert.Contains(t, log.String(), "Authorization: apikey [REDACTED]")
+	assert.Contains(t, log.String(), "not-secret")
+	assert.Contains(t, log.String(), "X-Amz-Signature=%5BREDACTED%5D")
+	assert.Contains(t, log.String(), "<19 bytes omitted>")
+	assert.Contains(t, string(har), `"version": "1.2"`)
+	assert.Contains(t, string(har), "19 bytes omitted")
+	for _, output := range []string{log.String(), string(har)} {
+		for _, secret := range []string{"sgu_not_an_actual_token", "request-secret", "response-secret", "not-an-actual-signature",
+			"not-an-actual-response-signature", "not-an-actual-state"} {
+			assert.NotContains(t, output, secret)
+		}
+	}
+}
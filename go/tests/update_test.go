@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	workflowcmd "github.com/StackGuardian/sg-cli/cmd/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+const remoteWorkflow = `{"msg": {
+    "ResourceName": "not-an-actual-workflow",
+    "Description": "old",
+    "Tags": ["a"],
+    "TerraformConfig": {"terraformVersion": "1.5.0", "managedTerraformState": true},
+    "LatestWfrunStatus": "COMPLETED"
+}}`
+
+func TestUpdateWorkflow(t *testing.T) {
+	payload := filepath.Join(t.TempDir(), "update.json")
+	if err := os.WriteFile(payload, []byte(`{"ResourceName": "not-an-actual-workflow", "Description": "new", "Tags": ["a", "b"],
+		"TerraformConfig": {"terraformVersion": "1.6.0"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	const workflowPath = "/api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow"
+
+	t.Run("Diff_And_Update", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{remoteWorkflow, `{"msg": "Workflow not-an-actual-workflow updated"}`}}
+		stdout, _, err := runWorkflow(t, sequence, "update", payload)
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, `>> Changes to workflow not-an-actual-workflow:
+~ $.Description: "old" -> "new"
++ $.Tags[1]: "b"
+- $.TerraformConfig.managedTerraformState: true
+~ $.TerraformConfig.terraformVersion: "1.5.0" -> "1.6.0"
+`)
+		assert.Contains(t, stdout, "Workflow updated successfully.\n")
+		assert.Equal(t, []string{"GET " + workflowPath, "PATCH " + workflowPath}, sequence.paths)
+
+		var sent map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(sequence.sent[1]), &sent))
+		assert.Equal(t, map[string]interface{}{
+			"ResourceName":    "not-an-actual-workflow",
+			"Description":     "new",
+			"Tags":            []interface{}{"a", "b"},
+			"TerraformConfig": map[string]interface{}{"terraformVersion": "1.6.0"},
+		}, sent)
+	})
+
+	t.Run("Dry_Run_With_Patch_Payload", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{remoteWorkflow}}
+		stdout, _, err := runWorkflow(t, sequence, "update", payload, "--patch-payload", `{"Description": "old", "Tags": ["a"]}`, "--dry-run")
+		assert.NoError(t, err)
+		assert.NotContains(t, stdout, "$.Description")
+		assert.Contains(t, stdout, `"terraformVersion": "1.6.0"`)
+		assert.Equal(t, []string{"GET " + workflowPath}, sequence.paths)
+	})
+
+	t.Run("Up_To_Date", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{remoteWorkflow}}
+		stdout, _, err := runWorkflow(t, sequence, "update", payload, "--patch-payload",
+			`{"Description": "old", "Tags": ["a"], "TerraformConfig": {"terraformVersion": "1.5.0", "managedTerraformState": true}}`)
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, ">> Workflow not-an-actual-workflow is up to date, nothing to update.\n")
+		assert.Len(t, sequence.paths, 1)
+	})
+
+	t.Run("Unknown_Field", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{remoteWorkflow}}
+		_, _, err := runWorkflow(t, sequence, "update", payload, "--patch-payload", `{"Descripton": "typo"}`)
+		assert.Equal(t, 5, clierrors.ExitCode(err), err)
+		assert.ErrorContains(t, err, "unknown fields in the workflow payload: Descripton")
+		assert.Empty(t, sequence.paths)
+	})
+
+	t.Run("Secrets_Are_Redacted", func(t *testing.T) {
+		sequence := &sequenceTransport{bodies: []string{`{"msg": {"ResourceName": "not-an-actual-workflow", "EnvironmentVariables": [
+			{"kind": "PLAIN_TEXT", "config": {"varName": "LOG_LEVEL", "textValue": "info"}},
+			{"kind": "PLAIN_TEXT", "config": {"varName": "API_TOKEN", "textValue": "old-token"}}
+		]}}`, `{"msg": "Workflow not-an-actual-workflow updated"}`}}
+		stdout, _, err := runWorkflow(t, sequence, "update", payload, "--patch-payload", `{"EnvironmentVariables": [
+			{"kind": "PLAIN_TEXT", "config": {"varName": "API_TOKEN", "textValue": "new-token"}},
+			{"kind": "PLAIN_TEXT", "config": {"varName": "LOG_LEVEL", "textValue": "debug"}}
+		]}`)
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, `~ $.EnvironmentVariables[varName=API_TOKEN].config.textValue: "(sensitive)" -> "(sensitive)"
+~ $.EnvironmentVariables[varName=LOG_LEVEL].config.textValue: "info" -> "debug"
+`)
+		assert.NotContains(t, stdout, "token\"")
+	})
+
+	t.Run("Not_An_Object", func(t *testing.T) {
+		array := filepath.Join(t.TempDir(), "array.json")
+		if err := os.WriteFile(array, []byte(`[]`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		for _, args := range [][]string{{payload, "--patch-payload", "null"}, {payload, "--patch-payload", `["a"]`}, {array, "--patch-payload", "{}"}} {
+			sequence := &sequenceTransport{bodies: []string{remoteWorkflow}}
+			_, _, err := runWorkflow(t, sequence, append([]string{"update"}, args...)...)
+			assert.Equal(t, 5, clierrors.ExitCode(err), args)
+			assert.Empty(t, sequence.paths)
+		}
+	})
+
+	t.Run("Bulk_Create_Updates_Existing", func(t *testing.T) {
+		sequence := &sequenceTransport{
+			bodies:   []string{`{"msg": "Workflow name not unique"}`, `{"msg": "Workflow not-an-actual-workflow updated"}`},
+			statuses: []int{http.StatusBadRequest},
+		}
+		_, _, err := runCmd(t, workflowcmd.NewWorkflowCmd, sequence, "",
+			[]string{"create", "--bulk", "--", filepath.Join(samplePayloadsDir, "create_single_wf_in_bulk_request.json")}, testFlags...)
+		assert.NoError(t, err)
+		assert.Len(t, sequence.paths, 2)
+		// The bulk payload sets its own workflow group
+		assert.Equal(t, "PATCH /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-wfg/wfs/not-an-actual-workflow", sequence.paths[1])
+		var sent map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(sequence.sent[1]), &sent))
+		assert.Equal(t, "not-an-actual-workflow", sent["ResourceName"])
+	})
+}
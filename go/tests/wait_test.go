@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/StackGuardian/sg-cli/clierrors"
+	workflowcmd "github.com/StackGuardian/sg-cli/cmd/workflow"
+	"github.com/StackGuardian/sg-sdk-go/client"
+	option "github.com/StackGuardian/sg-sdk-go/option"
+	"github.com/stretchr/testify/assert"
+)
+
+// sequenceTransport answers the requests with the bodies in order, repeating the last one, and records the request paths, queries and bodies.
+// statuses sets the status codes of the first responses, 200 by default.
+type sequenceTransport struct {
+	bodies   []string
+	statuses []int
+	paths    []string
+	queries  []string
+	sent     []string
+}
+
+func (s *sequenceTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	body := s.bodies[min(len(s.paths), len(s.bodies)-1)]
+	status := http.StatusOK
+	if len(s.paths) < len(s.statuses) && s.statuses[len(s.paths)] != 0 {
+		status = s.statuses[len(s.paths)]
+	}
+	s.paths = append(s.paths, request.Method+" "+request.URL.Path)
+	s.queries = append(s.queries, request.URL.RawQuery)
+	sent := []byte{}
+	if request.Body != nil {
+		sent, _ = io.ReadAll(request.Body)
+	}
+	s.sent = append(s.sent, string(sent))
+	return &http.Response{
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+		StatusCode: status,
+	}, nil
+}
+
+func runStatus(status string) string {
+	return `{"msg": {"ResourceName": "not-an-actual-workflow-run", "LatestStatus": "` + status + `", "CreatedAt": 1729823822294}}`
+}
+
+const createdRun = `{"msg": "Workflow Run dispatched", "data": {"ResourceName": "not-an-actual-workflow-run", "LatestStatus": "QUEUED"}}`
+
+func TestWaitForWorkflowRun(t *testing.T) {
+	flags := []string{"--org", "not-an-actual-org", "--workflow-group", "not-an-actual-workflow-group",
+		"--workflow-id", "not-an-actual-workflow", "--poll-interval", "1ms"}
+
+	cases := []struct {
+		name             string
+		args             []string
+		bodies           []string
+		timeout          time.Duration
+		expectedExitCode int
+		expectedStatuses []string
+		expectedRequests int
+	}{
+		{
+			name:             "Apply_Completed",
+			args:             []string{"apply", "--wait"},
+			bodies:           []string{createdRun, runStatus("QUEUED"), runStatus("QUEUED"), runStatus("RUNNING"), runStatus("COMPLETED")},
+			expectedExitCode: 0,
+			expectedStatuses: []string{"QUEUED", "RUNNING", "COMPLETED"},
+			expectedRequests: 5,
+		},
+		{
+			name:             "Destroy_Errored",
+			args:             []string{"destroy", "--wait"},
+			bodies:           []string{createdRun, runStatus("RUNNING"), runStatus("ERRORED")},
+			expectedExitCode: 11,
+			expectedStatuses: []string{"RUNNING", "ERRORED"},
+			expectedRequests: 3,
+		},
+		{
+			name:             "Wait_Rejected",
+			args:             []string{"wait", "not-an-actual-workflow-run"},
+			bodies:           []string{runStatus("APPROVAL_REQUIRED"), runStatus("REJECTED")},
+			expectedExitCode: 12,
+			expectedStatuses: []string{"APPROVAL_REQUIRED", "REJECTED"},
+			expectedRequests: 2,
+		},
+		{
+			name:             "Wait_Until_Approval_Required",
+			args:             []string{"wait", "not-an-actual-workflow-run", "--until", "APPROVAL_REQUIRED"},
+			bodies:           []string{runStatus("RUNNING"), runStatus("APPROVAL_REQUIRED"), runStatus("COMPLETED")},
+			expectedExitCode: 0,
+			expectedStatuses: []string{"RUNNING", "APPROVAL_REQUIRED"},
+			expectedRequests: 2,
+		},
+		{
+			name:             "Wait_Timeout",
+			args:             []string{"wait", "not-an-actual-workflow-run"},
+			bodies:           []string{runStatus("RUNNING")},
+			timeout:          50 * time.Millisecond,
+			expectedExitCode: 124,
+			expectedStatuses: []string{"RUNNING"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			transport := &sequenceTransport{bodies: tc.bodies}
+			cmd := workflowcmd.NewWorkflowCmd(client.NewClient(option.WithHTTPClient(&http.Client{Transport: transport})))
+			cmd.SetArgs(append(tc.args, flags...))
+			b := &bytes.Buffer{}
+			cmd.SetOut(b)
+			cmd.SetErr(io.Discard)
+			cmd.SilenceErrors = true
+			// The global --timeout cancels the context of the command
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+			err := cmd.ExecuteContext(ctx)
+
+			assert.Equal(t, tc.expectedExitCode, clierrors.ExitCode(err), err)
+			for _, status := range tc.expectedStatuses {
+				assert.Equal(t, 1, bytes.Count(b.Bytes(), []byte("] Workflow run not-an-actual-workflow-run: "+status+"\n")), status)
+			}
+			if tc.expectedRequests > 0 {
+				assert.Len(t, transport.paths, tc.expectedRequests)
+			}
+			assert.Contains(t, transport.paths, "GET /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/not-an-actual-workflow/wfruns/not-an-actual-workflow-run/")
+		})
+	}
+
+	t.Run("Wait_Requires_Run", func(t *testing.T) {
+		cmd := workflowcmd.NewWorkflowCmd(client.NewClient(option.WithHTTPClient(&http.Client{Transport: &sequenceTransport{bodies: []string{createdRun}}})))
+		cmd.SetArgs([]string{"create", "--org", "not-an-actual-org", "--workflow-group", "not-an-actual-workflow-group", "--wait", "--", "payload.json"})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		err := cmd.Execute()
+		assert.Equal(t, 2, clierrors.ExitCode(err))
+	})
+}
@@ -265,7 +265,7 @@ func TestWorkflowE2E(t *testing.T) {
 
 	t.Run("Negative_Tests-Missing_ResourceName_In_Payload", func(t *testing.T) {
 		// Create with invalid JSON payload
-		// This create request will fail with the error "Workflow ResourceName is required in object payload, skipping"
+		// This create request will fail with the error "Workflow ResourceName is required in object payload"
 		createArgs := []string{
 			cmdWorkflow, actionCreate,
 			flagOrg, orgName,
@@ -5,14 +5,17 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
 
 	workflowcmd "github.com/StackGuardian/sg-cli/cmd/workflow"
+	"github.com/StackGuardian/sg-cli/transport"
 	api "github.com/StackGuardian/sg-sdk-go"
 	"github.com/StackGuardian/sg-sdk-go/client"
 	option "github.com/StackGuardian/sg-sdk-go/option"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -410,9 +413,12 @@ func TestListWorkflow(t *testing.T) {
 		expectedByte   []byte
 	}{
 		{
-			name:           "Success",
-			expectedString: "> Workflow Name:  not-an-actual-workflow\nDescription:  test desc\n\n> Workflow Name:  not-an-actual-workflow-2\nDescription:  test desc\n\n",
-			expectedByte:   successExpected,
+			name: "Success",
+			expectedString: "" +
+				"NAME                       TYPE     STATUS      TAGS   MODIFIED               DESCRIPTION\n" +
+				"not-an-actual-workflow     CUSTOM   UNTRACKED          2024-10-25T02:37:34Z   test desc\n" +
+				"not-an-actual-workflow-2   CUSTOM   ERRORED            2024-10-18T12:29:39Z   test desc\n",
+			expectedByte: successExpected,
 		},
 	}
 
@@ -693,3 +699,25 @@ func TestBulkCreateWorkflow(t *testing.T) {
 	}
 
 }
+
+func TestBulkCreateWorkflowState(t *testing.T) {
+	t.Setenv("SG_CONFIG_FILE", filepath.Join(t.TempDir(), "config.yaml"))
+	t.Cleanup(func() { transport.SetClient(nil) })
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "demo.tfstate")
+	assert.NoError(t, os.WriteFile(stateFile, []byte(`{"version": 4}`), 0o600))
+	payloadFile := filepath.Join(dir, "workflows.json")
+	assert.NoError(t, os.WriteFile(payloadFile, []byte(`[{"ResourceName": "demo", "CLIConfiguration": {"TfStateFilePath": "`+stateFile+`"}}]`), 0o600))
+
+	state := &sequenceTransport{bodies: []string{`{"msg": "https://storage.example.com/upload.tfstate"}`, ``}}
+	transport.SetClient(&http.Client{Transport: state})
+	sequence := &sequenceTransport{bodies: []string{`{"msg": "Workflow demo created", "data": {"ResourceName": "demo"}}`}}
+	stdout, _, err := runCmd(t, workflowcmd.NewWorkflowCmd, sequence, "", []string{"create", "--bulk", "--", payloadFile}, testFlags...)
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, ">> State file uploaded successfully.\n")
+	assert.Equal(t, []string{
+		"GET /api/v1/orgs/not-an-actual-org/wfgrps/not-an-actual-workflow-group/wfs/demo/tfstate_upload_url",
+		"PUT /upload.tfstate",
+	}, state.paths)
+	assert.Equal(t, `{"version": 4}`, state.sent[1])
+}
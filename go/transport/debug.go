@@ -0,0 +1,425 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	redacted = "[REDACTED]"
+	// maxLoggedBody is the number of bytes of a body printed to the debug log
+	maxLoggedBody = 16 << 10
+)
+
+// DebugLog receives the wire trace of every request when set, see --debug
+var DebugLog io.Writer
+
+// secretHeaders are never logged
+var secretHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// secretFields are JSON keys whose values are never logged, compared case-insensitively
+var secretFields = map[string]bool{
+	"awssecretaccesskey": true,
+	"awssessiontoken":    true,
+	"textvalue":          true,
+	"password":           true,
+	"privatekey":         true,
+	"clientsecret":       true,
+	"apitoken":           true,
+	"token":              true,
+}
+
+// secretQueryParameters are redacted from URLs, e.g. the signature of the tfstate upload URL
+var secretQueryParameters = []string{"X-Amz-Signature", "X-Amz-Credential", "X-Amz-Security-Token", "Signature", "sig"}
+
+// requestIDHeaders are the headers the API may return the ID of a request in
+var requestIDHeaders = []string{"X-Request-Id", "X-Amzn-Requestid", "X-Amz-Apigw-Id"}
+
+// debugTransport logs every request and response with secrets redacted,
+// and records them to the HAR file when enabled
+type debugTransport struct {
+	next http.RoundTripper
+	// apiHost is the host of the API, the bodies of other hosts are not captured.
+	// When it is empty, only the bodies of pre-signed URLs are not captured.
+	apiHost string
+}
+
+// capturesBody reports whether the bodies of a request are logged. Pre-signed URLs and hosts other
+// than the API transfer files such as the tfstate, which are neither read into memory nor logged.
+func (t *debugTransport) capturesBody(u *url.URL) bool {
+	if t.apiHost != "" && u.Host != t.apiHost {
+		return false
+	}
+	return !signed(u)
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	capture := t.capturesBody(req.URL)
+	var requestBody []byte
+	if capture {
+		var err error
+		if requestBody, err = peekRequestBody(req); err != nil {
+			return nil, err
+		}
+	}
+	requestURL := redactURL(req.URL)
+	if DebugLog != nil {
+		fmt.Fprintf(DebugLog, "--> %s %s\n", req.Method, requestURL)
+		writeHeaders(DebugLog, req.Header)
+		if capture {
+			writeBody(DebugLog, requestBody)
+		} else {
+			writeOmitted(DebugLog, req.ContentLength)
+		}
+	}
+
+	started := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(started)
+	if err != nil {
+		if DebugLog != nil {
+			fmt.Fprintf(DebugLog, "<-- %s %s failed after %s: %s\n\n", req.Method, requestURL, elapsed.Round(time.Millisecond), err)
+		}
+		return resp, err
+	}
+
+	var responseBody []byte
+	if capture {
+		responseBody, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+		if err != nil {
+			return resp, err
+		}
+	}
+	if DebugLog != nil {
+		fmt.Fprintf(DebugLog, "<-- %s %s %s (%s)", resp.Status, req.Method, requestURL, elapsed.Round(time.Millisecond))
+		if requestID := requestID(resp.Header); requestID != "" {
+			fmt.Fprintf(DebugLog, " request-id: %s", requestID)
+		}
+		fmt.Fprintln(DebugLog)
+		writeHeaders(DebugLog, resp.Header)
+		if capture {
+			writeBody(DebugLog, responseBody)
+		} else {
+			writeOmitted(DebugLog, resp.ContentLength)
+		}
+	}
+	har.record(req, requestURL, requestBody, resp, responseBody, capture, started, elapsed)
+	return resp, nil
+}
+
+// signed reports whether a URL is pre-signed, i.e. has a signature in its query
+func signed(u *url.URL) bool {
+	query := u.Query()
+	for _, name := range secretQueryParameters {
+		if query.Has(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// omitted describes a body that is not captured, size is -1 when it is unknown
+func omitted(size int64) string {
+	if size < 0 {
+		return "<body omitted>"
+	}
+	return fmt.Sprintf("<%d bytes omitted>", size)
+}
+
+// peekRequestBody returns the request body and leaves the request ready to be sent
+func peekRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, err
+}
+
+func requestID(header http.Header) string {
+	for _, name := range requestIDHeaders {
+		if value := header.Get(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func writeHeaders(w io.Writer, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "    %s: %s\n", name, redactHeader(name, strings.Join(header[name], ", ")))
+	}
+}
+
+func writeBody(w io.Writer, body []byte) {
+	if len(body) > 0 {
+		text := RedactBody(body)
+		if len(text) > maxLoggedBody {
+			text = text[:maxLoggedBody] + fmt.Sprintf("... (%d bytes truncated)", len(text)-maxLoggedBody)
+		}
+		fmt.Fprintln(w, "    "+strings.ReplaceAll(text, "\n", "\n    "))
+	}
+	fmt.Fprintln(w)
+}
+
+func writeOmitted(w io.Writer, size int64) {
+	if size != 0 {
+		fmt.Fprintln(w, "    "+omitted(size))
+	}
+	fmt.Fprintln(w)
+}
+
+func redactHeader(name, value string) string {
+	if !secretHeaders[http.CanonicalHeaderKey(name)] {
+		return value
+	}
+	// Keep the scheme, e.g. "apikey", so it is still visible which kind of credential was sent
+	if scheme, _, found := strings.Cut(value, " "); found {
+		return scheme + " " + redacted
+	}
+	return redacted
+}
+
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	changed := false
+	for _, name := range secretQueryParameters {
+		if query.Has(name) {
+			query.Set(name, redacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return u.Redacted()
+	}
+	copied := *u
+	copied.RawQuery = query.Encode()
+	return copied.Redacted()
+}
+
+// RedactBody returns the body with the values of secret fields replaced. Bodies that are not JSON are returned as is.
+func RedactBody(body []byte) string {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return string(body)
+	}
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return string(body)
+	}
+	return string(redacted)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if secretFields[strings.ToLower(key)] {
+				if field != nil && field != "" {
+					v[key] = redacted
+				}
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	case string:
+		// Pre-signed URLs returned by the API, e.g. the msg of tfstate_upload_url
+		if strings.HasPrefix(v, "http") {
+			if u, err := url.Parse(v); err == nil && signed(u) {
+				return redactURL(u)
+			}
+		}
+	}
+	return value
+}
+
+// harLog collects the requests for the HAR file, see RecordHAR
+type harLog struct {
+	mu      sync.Mutex
+	entries []harEntry
+}
+
+var har *harLog
+
+type harEntry struct {
+	StartedDateTime string                 `json:"startedDateTime"`
+	Time            float64                `json:"time"`
+	Request         harRequest             `json:"request"`
+	Response        harResponse            `json:"response"`
+	Cache           map[string]interface{} `json:"cache"`
+	Timings         harTimings             `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// RecordHAR starts recording all requests, they are written by WriteHAR
+func RecordHAR() {
+	har = &harLog{}
+}
+
+func (h *harLog) record(req *http.Request, requestURL string, requestBody []byte, resp *http.Response, responseBody []byte, captured bool, started time.Time, elapsed time.Duration) {
+	if h == nil {
+		return
+	}
+	milliseconds := float64(elapsed.Microseconds()) / 1000
+	entry := harEntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Time:            milliseconds,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         requestURL,
+			HTTPVersion: req.Proto,
+			Headers:     harHeaders(req.Header),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(requestBody),
+		},
+		Response: harResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(resp.StatusCode),
+			HTTPVersion: resp.Proto,
+			Headers:     harHeaders(resp.Header),
+			Content: harContent{
+				Size:     len(responseBody),
+				MimeType: resp.Header.Get("Content-Type"),
+				Text:     RedactBody(responseBody),
+			},
+			HeadersSize: -1,
+			BodySize:    len(responseBody),
+		},
+		Cache:   map[string]interface{}{},
+		Timings: harTimings{Send: 0, Wait: milliseconds, Receive: 0},
+	}
+	if parsed, err := url.Parse(requestURL); err == nil {
+		for name, values := range parsed.Query() {
+			for _, value := range values {
+				entry.Request.QueryString = append(entry.Request.QueryString, harNameValue{Name: name, Value: value})
+			}
+		}
+	}
+	if len(requestBody) > 0 {
+		entry.Request.PostData = &harPostData{MimeType: req.Header.Get("Content-Type"), Text: RedactBody(requestBody)}
+	}
+	if !captured {
+		entry.Request.BodySize = int(req.ContentLength)
+		if req.ContentLength != 0 {
+			entry.Request.PostData = &harPostData{MimeType: req.Header.Get("Content-Type"), Text: omitted(req.ContentLength)}
+		}
+		entry.Response.BodySize = int(resp.ContentLength)
+		entry.Response.Content.Size = int(resp.ContentLength)
+		entry.Response.Content.Text = omitted(resp.ContentLength)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = append(h.entries, entry)
+}
+
+func harHeaders(header http.Header) []harNameValue {
+	headers := []harNameValue{}
+	for name, values := range header {
+		for _, value := range values {
+			headers = append(headers, harNameValue{Name: name, Value: redactHeader(name, value)})
+		}
+	}
+	sort.Slice(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
+	return headers
+}
+
+// WriteHAR writes the requests recorded since RecordHAR to a HAR 1.2 file, see http://www.softwareishard.com/blog/har-12-spec/
+func WriteHAR(path, version string) error {
+	if har == nil {
+		return nil
+	}
+	har.mu.Lock()
+	defer har.mu.Unlock()
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"log": map[string]interface{}{
+			"version": "1.2",
+			"creator": map[string]string{"name": "sg-cli", "version": version},
+			"entries": har.entries,
+		},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
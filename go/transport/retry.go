@@ -0,0 +1,170 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts is used when max-attempts is not set
+	DefaultMaxAttempts = 4
+	minRetryDelay      = 500 * time.Millisecond
+	maxRetryDelay      = 30 * time.Second
+)
+
+// Log receives a line for every retried request when set, see --verbose
+var Log io.Writer
+
+var retries atomic.Int64
+
+// Retries returns the number of requests that have been retried so far
+func Retries() int64 {
+	return retries.Load()
+}
+
+// retryTransport retries transient failures with exponential backoff and full jitter.
+// Idempotent requests are retried on network errors, 408, 429 and 5xx responses.
+// Other requests, like the POST creating a workflow, are only retried on 429
+// because the API rejected them before doing anything.
+type retryTransport struct {
+	next        http.RoundTripper
+	maxAttempts int
+	// sleep waits between attempts, it returns early with an error when the request is cancelled
+	sleep func(req *http.Request, d time.Duration) error
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.maxAttempts || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+		// The body can only be sent again if it can be recreated
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, err
+		}
+
+		delay := backoff(attempt)
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				delay = retryAfter
+			}
+			// Drain the body so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+			resp.Body.Close()
+		}
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+
+		retries.Add(1)
+		if Log != nil {
+			fmt.Fprintf(Log, ">> Retrying %s %s in %s (attempt %d/%d): %s\n",
+				req.Method, req.URL.Redacted(), delay.Round(time.Millisecond), attempt+1, t.maxAttempts, reason)
+		}
+		if err := t.sleep(req, delay); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if !idempotent(req.Method) {
+		return false
+	}
+	if err != nil {
+		return !permanent(err)
+	}
+	return resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// permanent reports whether a network error will not go away by retrying, like an untrusted certificate
+func permanent(err error) bool {
+	var (
+		verificationError *tls.CertificateVerificationError
+		alertError        tls.AlertError
+		recordHeaderError tls.RecordHeaderError
+		unknownAuthority  x509.UnknownAuthorityError
+		hostnameError     x509.HostnameError
+		invalidError      x509.CertificateInvalidError
+	)
+	if errors.As(err, &verificationError) || errors.As(err, &alertError) || errors.As(err, &recordHeaderError) ||
+		errors.As(err, &unknownAuthority) || errors.As(err, &hostnameError) || errors.As(err, &invalidError) {
+		return true
+	}
+	// Alerts sent by the server, e.g. when it requires a client certificate, have no exported type
+	return strings.Contains(err.Error(), "remote error: tls:")
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// backoff returns a random delay between 0 and minRetryDelay * 2^(attempt-1), capped at maxRetryDelay
+func backoff(attempt int) time.Duration {
+	ceiling := minRetryDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > maxRetryDelay {
+		ceiling = maxRetryDelay
+	}
+	return time.Duration(rand.Int63n(int64(ceiling)))
+}
+
+// parseRetryAfter supports both forms of the Retry-After header: seconds and an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+func sleepContext(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
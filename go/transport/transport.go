@@ -0,0 +1,111 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/StackGuardian/sg-cli/config"
+)
+
+var shared *http.Client
+
+// New builds the HTTP client used for all calls to Stackguardian from the connection settings:
+// ca-file, client-cert, client-key, proxy and request-timeout.
+// Transient failures are retried up to max-attempts times, see retryTransport.
+// Every attempt is traced when DebugLog is set or a HAR file is recorded.
+// Without a proxy setting, HTTPS_PROXY, HTTP_PROXY and NO_PROXY from the environment are honored.
+func New(settings *config.Settings) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if settings.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(settings.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca-file: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca-file %s does not contain any PEM encoded certificate", settings.CAFile)
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	if settings.ClientCert != "" || settings.ClientKey != "" {
+		if settings.ClientCert == "" || settings.ClientKey == "" {
+			return nil, fmt.Errorf("client-cert and client-key must be set together")
+		}
+		certificate, err := tls.LoadX509KeyPair(settings.ClientCert, settings.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	if settings.Proxy != "" {
+		proxyURL, err := url.Parse(settings.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy %q, expected a URL such as http://proxy.example.com:3128", settings.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	// Requests do not time out unless request-timeout is set
+	var timeout time.Duration
+	if settings.RequestTimeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(settings.RequestTimeout)
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("invalid request-timeout %q, expected a duration such as 30s or 2m", settings.RequestTimeout)
+		}
+	}
+
+	maxAttempts := DefaultMaxAttempts
+	if settings.MaxAttempts != "" {
+		var err error
+		maxAttempts, err = strconv.Atoi(settings.MaxAttempts)
+		if err != nil || maxAttempts < 1 {
+			return nil, fmt.Errorf("invalid max-attempts %q, expected a number of at least 1", settings.MaxAttempts)
+		}
+	}
+
+	var next http.RoundTripper = transport
+	if DebugLog != nil || har != nil {
+		debug := &debugTransport{next: transport}
+		if apiURL, err := url.Parse(settings.APIURL); err == nil {
+			debug.apiHost = apiURL.Host
+		}
+		next = debug
+	}
+	return &http.Client{
+		Transport: &retryTransport{next: next, maxAttempts: maxAttempts, sleep: sleepContext},
+		Timeout:   timeout,
+	}, nil
+}
+
+// SetClient makes the given client the one returned by Client
+func SetClient(c *http.Client) {
+	shared = c
+}
+
+// Client returns the shared HTTP client. Raw HTTP calls must use it instead of http.DefaultClient
+// so they get the same connection settings as the SDK. If none has been set yet, it is built
+// from the current settings.
+func Client() *http.Client {
+	if shared == nil {
+		c, err := New(config.Current())
+		if err != nil {
+			return http.DefaultClient
+		}
+		shared = c
+	}
+	return shared
+}
@@ -5,6 +5,12 @@ import (
 	"reflect"
 )
 
+// IsObject reports whether data is a JSON object, PatchJSON only merges objects
+func IsObject(data string) bool {
+	var object map[string]interface{}
+	return json.Unmarshal([]byte(data), &object) == nil && object != nil
+}
+
 // PatchJson takes two JSON strings and merges the second JSON string into the first JSON string
 func PatchJSON(originalJSON, patchJSON string) string {
 	var originalJSONUnMarshalled map[string]interface{}